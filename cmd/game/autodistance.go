@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+
+	"voxel-game/internal/world"
+)
+
+// renderDistanceTuner does the first-run render distance detection. It starts
+// conservative and steps up one chunk at a time while FPS holds above target,
+// backing off once and stopping as soon as a step costs too much.
+type renderDistanceTuner struct {
+	targetFPS   float64
+	maxDistance int
+	// Consecutive healthy FPS samples required before stepping up
+	settleSamples int
+
+	// Samples ignored while new chunks generate, which always dips FPS
+	skipSamples int
+	goodSamples int
+	done        bool
+}
+
+func newRenderDistanceTuner(w *world.World) *renderDistanceTuner {
+	w.SetRenderDistance(4)
+	return &renderDistanceTuner{
+		targetFPS:     55,
+		maxDistance:   12,
+		settleSamples: 3,
+		skipSamples:   3,
+	}
+}
+
+// Sample feeds one FPS measurement (taken about once a second). Returns true
+// once the tuner has settled on a distance.
+func (t *renderDistanceTuner) Sample(fps float64, w *world.World) bool {
+	if t.done {
+		return true
+	}
+	if t.skipSamples > 0 {
+		t.skipSamples--
+		return false
+	}
+
+	if fps < t.targetFPS*0.9 {
+		if w.RenderDistance() > 4 {
+			w.SetRenderDistance(w.RenderDistance() - 1)
+		}
+		return t.finish(w)
+	}
+
+	if fps < t.targetFPS {
+		t.goodSamples = 0
+		return false
+	}
+
+	t.goodSamples++
+	if t.goodSamples < t.settleSamples {
+		return false
+	}
+	t.goodSamples = 0
+
+	if w.RenderDistance() >= t.maxDistance {
+		return t.finish(w)
+	}
+	w.SetRenderDistance(w.RenderDistance() + 1)
+	t.skipSamples = 2
+	return false
+}
+
+func (t *renderDistanceTuner) finish(w *world.World) bool {
+	t.done = true
+	log.Printf("Render distance: %d chunks (auto-detected)", w.RenderDistance())
+	return true
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"voxel-game/internal/player"
+	"voxel-game/internal/ui"
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// consoleCommand is one command the console runs. run gets the words after
+// the command name and returns a line of output.
+type consoleCommand struct {
+	usage string
+	run   func(args []string) (string, error)
+}
+
+// consoleCommands runs typed console lines against the game
+type consoleCommands struct {
+	world    *world.World
+	player   *player.Player
+	clock    *world.DayClock
+	commands map[string]consoleCommand
+}
+
+func newConsoleCommands(w *world.World, p *player.Player, clock *world.DayClock) *consoleCommands {
+	c := &consoleCommands{world: w, player: p, clock: clock}
+	c.commands = map[string]consoleCommand{
+		"help":     {"help", c.help},
+		"tp":       {"tp <x> <y> <z>", c.teleport},
+		"setblock": {"setblock <x> <y> <z> <block>", c.setBlock},
+		"seed":     {"seed", c.seed},
+		"time":     {"time [set <0-1|HH:MM|sunrise|noon|sunset|midnight>]", c.time},
+		"give":     {"give <block> [count]", c.give},
+	}
+	return c
+}
+
+// Run executes one console line
+func (c *consoleCommands) Run(line string) (string, error) {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return "", nil
+	}
+	command, ok := c.commands[strings.ToLower(words[0])]
+	if !ok {
+		return "", fmt.Errorf("unknown command %q, try help", words[0])
+	}
+	return command.run(words[1:])
+}
+
+func (c *consoleCommands) help(args []string) (string, error) {
+	usages := make([]string, 0, len(c.commands))
+	for _, command := range c.commands {
+		usages = append(usages, command.usage)
+	}
+	sort.Strings(usages)
+	return strings.Join(usages, "\n"), nil
+}
+
+// tpMinY and tpMaxY bound where tp can put the player's feet, from just
+// above the bottom of the world to a little over the build height
+const (
+	tpMinY = 1
+	tpMaxY = world.ChunkHeight + 64
+)
+
+// teleport moves the player's feet to x y z. ~ is the current coordinate,
+// ~5 five blocks past it. Y is clamped to tpMinY..tpMaxY.
+func (c *consoleCommands) teleport(args []string) (string, error) {
+	if len(args) != 3 {
+		return "", fmt.Errorf("usage: %s", c.commands["tp"].usage)
+	}
+	var pos mgl32.Vec3
+	for i, arg := range args {
+		v, err := parseCoord(arg, float64(c.player.PhysicsPos[i]))
+		if err != nil {
+			return "", err
+		}
+		pos[i] = float32(v)
+	}
+	pos[1] = max(tpMinY, min(pos[1], tpMaxY))
+	c.player.Teleport(pos)
+	return fmt.Sprintf("Teleported to %.1f, %.1f, %.1f", pos[0], pos[1], pos[2]), nil
+}
+
+// setBlock places a block, relative coordinates count from the block the
+// player stands in
+func (c *consoleCommands) setBlock(args []string) (string, error) {
+	if len(args) < 4 {
+		return "", fmt.Errorf("usage: %s", c.commands["setblock"].usage)
+	}
+	var cell [3]int
+	for i, arg := range args[:3] {
+		v, err := parseCoord(arg, math.Floor(float64(c.player.PhysicsPos[i])))
+		if err != nil {
+			return "", err
+		}
+		cell[i] = int(math.Floor(v))
+	}
+	blockType, err := parseBlock(strings.Join(args[3:], " "))
+	if err != nil {
+		return "", err
+	}
+	if !c.world.SetBlock(cell[0], cell[1], cell[2], blockType) {
+		return "", fmt.Errorf("can't set a block at %d, %d, %d", cell[0], cell[1], cell[2])
+	}
+	return fmt.Sprintf("Set %d, %d, %d to %s", cell[0], cell[1], cell[2], world.GetBlockDef(blockType).Name), nil
+}
+
+func (c *consoleCommands) seed(args []string) (string, error) {
+	return fmt.Sprintf("Seed: %d", c.world.Seed()), nil
+}
+
+func (c *consoleCommands) time(args []string) (string, error) {
+	if len(args) == 0 {
+		return "Time: " + clockText(c.clock), nil
+	}
+	if len(args) != 2 || strings.ToLower(args[0]) != "set" {
+		return "", fmt.Errorf("usage: %s", c.commands["time"].usage)
+	}
+	t, err := parseTimeOfDay(args[1])
+	if err != nil {
+		return "", err
+	}
+	c.clock.Set(t)
+	return "Time: " + c.clock.String(), nil
+}
+
+func (c *consoleCommands) give(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("usage: %s", c.commands["give"].usage)
+	}
+	// A trailing number is the count, block names may have spaces
+	count := 1
+	if n, err := strconv.Atoi(args[len(args)-1]); err == nil && len(args) > 1 {
+		count = n
+		args = args[:len(args)-1]
+	}
+	if count <= 0 {
+		return "", fmt.Errorf("count must be positive, got %d", count)
+	}
+	blockType, err := parseBlock(strings.Join(args, " "))
+	if err != nil {
+		return "", err
+	}
+	if blockType == world.BlockAir {
+		return "", fmt.Errorf("can't give air")
+	}
+
+	name := world.GetBlockDef(blockType).Name
+	left := c.player.Inventory.Add(blockType, count)
+	if left == count {
+		return "", fmt.Errorf("inventory full")
+	}
+	if left > 0 {
+		return fmt.Sprintf("Gave %d %s, %d didn't fit", count-left, name, left), nil
+	}
+	return fmt.Sprintf("Gave %d %s", count, name), nil
+}
+
+// maxCoord is the furthest coordinate the console accepts, where float32
+// positions stop holding whole blocks
+const maxCoord = 1 << 24
+
+// parseCoord reads a coordinate, or one relative to current when it starts
+// with ~
+func parseCoord(arg string, current float64) (float64, error) {
+	relative := strings.HasPrefix(arg, "~")
+	if relative {
+		arg = arg[1:]
+		if arg == "" {
+			return current, nil
+		}
+	}
+	v, err := strconv.ParseFloat(arg, 64)
+	// ParseFloat takes NaN and Inf, which would wreck physics and chunk
+	// lookups
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("bad coordinate %q", arg)
+	}
+	if relative {
+		v += current
+	}
+	if math.Abs(v) > maxCoord {
+		return 0, fmt.Errorf("coordinate %q out of range", arg)
+	}
+	return v, nil
+}
+
+// parseBlock looks a block up by name, ignoring case and with underscores
+// for spaces, or by its number
+func parseBlock(name string) (world.BlockType, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		if id < 0 || id > 255 || (id != int(world.BlockAir) && world.GetBlockDef(world.BlockType(id)).Name == "") {
+			return world.BlockAir, fmt.Errorf("no block number %d", id)
+		}
+		return world.BlockType(id), nil
+	}
+	name = strings.ReplaceAll(name, "_", " ")
+	for id := 0; id < 256; id++ {
+		if def := world.GetBlockDef(world.BlockType(id)); def.Name != "" && strings.EqualFold(def.Name, name) {
+			return world.BlockType(id), nil
+		}
+	}
+	return world.BlockAir, fmt.Errorf("unknown block %q", name)
+}
+
+// parseTimeOfDay reads a fraction of a day, a 24-hour HH:MM time or the
+// name of a preset
+func parseTimeOfDay(arg string) (float32, error) {
+	switch strings.ToLower(arg) {
+	case "sunrise":
+		return world.TimeSunrise, nil
+	case "noon":
+		return world.TimeNoon, nil
+	case "sunset":
+		return world.TimeSunset, nil
+	case "midnight":
+		return world.TimeMidnight, nil
+	}
+	if hours, minutes, ok := strings.Cut(arg, ":"); ok {
+		h, errH := strconv.Atoi(hours)
+		m, errM := strconv.Atoi(minutes)
+		if errH != nil || errM != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+			return 0, fmt.Errorf("bad time %q", arg)
+		}
+		return float32(h*60+m) / (24 * 60), nil
+	}
+	t, err := strconv.ParseFloat(arg, 32)
+	if err != nil || t < 0 || t > 1 {
+		return 0, fmt.Errorf("bad time %q, use 0 to 1 or HH:MM", arg)
+	}
+	return float32(t), nil
+}
+
+// handleConsoleKey edits the console input or runs it on Enter
+func handleConsoleKey(console *ui.Console, commands *consoleCommands, key glfw.Key) {
+	switch key {
+	case glfw.KeyEnter, glfw.KeyKPEnter:
+		line := console.Submit()
+		if line == "" {
+			return
+		}
+		output, err := commands.Run(line)
+		if err != nil {
+			console.PrintError(err.Error())
+			return
+		}
+		for _, text := range strings.Split(output, "\n") {
+			if text != "" {
+				console.Print(text)
+			}
+		}
+	case glfw.KeyBackspace:
+		console.Backspace()
+	case glfw.KeyUp:
+		console.HistoryPrev()
+	case glfw.KeyDown:
+		console.HistoryNext()
+	case glfw.KeyPageUp:
+		console.Scroll(1)
+	case glfw.KeyPageDown:
+		console.Scroll(-1)
+	}
+}
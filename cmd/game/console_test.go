@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"voxel-game/internal/world"
+)
+
+func TestParseCoord(t *testing.T) {
+	tests := []struct {
+		arg     string
+		current float64
+		want    float64
+		wantErr bool
+	}{
+		{"~", 12.5, 12.5, false},
+		{"~5", 12.5, 17.5, false},
+		{"~-5", 12.5, 7.5, false},
+		{"~0.25", -3, -2.75, false},
+		{"100", 12.5, 100, false},
+		{"-64.5", 12.5, -64.5, false},
+		{"16777216", 0, 16777216, false},
+		{"NaN", 0, 0, true},
+		{"~NaN", 0, 0, true},
+		{"Inf", 0, 0, true},
+		{"-inf", 0, 0, true},
+		{"1e30", 0, 0, true},
+		{"~1e30", 0, 0, true},
+		{"16777217", 0, 0, true},
+		{"~1", maxCoord, 0, true},
+		{"", 0, 0, true},
+		{"~x", 0, 0, true},
+		{"abc", 0, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseCoord(tt.arg, tt.current)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseCoord(%q, %v) = %v, want an error", tt.arg, tt.current, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseCoord(%q, %v) = %v, %v, want %v", tt.arg, tt.current, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    float32
+		wantErr bool
+	}{
+		{"sunrise", world.TimeSunrise, false},
+		{"sunset", world.TimeSunset, false},
+		{"SUNSET", world.TimeSunset, false},
+		{"noon", world.TimeNoon, false},
+		{"midnight", world.TimeMidnight, false},
+		{"06:00", 0.25, false},
+		{"18:00", 0.75, false},
+		{"0:00", 0, false},
+		{"23:59", float32(23*60+59) / (24 * 60), false},
+		{"0.5", 0.5, false},
+		{"1", 1, false},
+		{"24:00", 0, true},
+		{"12:60", 0, true},
+		{"-1:00", 0, true},
+		{"12:", 0, true},
+		{"1.5", 0, true},
+		{"-0.1", 0, true},
+		{"dusk", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeOfDay(tt.arg)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseTimeOfDay(%q) = %v, want an error", tt.arg, got)
+			}
+			continue
+		}
+		if err != nil || math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("parseTimeOfDay(%q) = %v, %v, want %v", tt.arg, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    world.BlockType
+		wantErr bool
+	}{
+		{"stone", world.BlockStone, false},
+		{"Stone", world.BlockStone, false},
+		{"coal_ore", world.BlockCoalOre, false},
+		{"Iron_Ore", world.BlockIronOre, false},
+		{"3", world.BlockStone, false},
+		{"12", world.BlockIronOre, false},
+		{"0", world.BlockAir, false},
+		{"air", world.BlockAir, false},
+		{"coalore", 0, true},
+		{"diamond", 0, true},
+		{"-1", 0, true},
+		{"200", 0, true},
+		{"256", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseBlock(tt.name)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseBlock(%q) = %v, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseBlock(%q) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Frame cap setting values besides a frames per second number
+const (
+	frameCapVSync    = 0
+	frameCapUncapped = -1
+)
+
+// frameCapSteps is the order the frame cap cycles through at runtime
+var frameCapSteps = []int{frameCapVSync, 30, 60, 120, 144, frameCapUncapped}
+
+// frameLimiter paces the game loop: VSync, uncapped, or a fixed frame rate
+// by sleeping off whatever is left of each frame. Delta time is measured
+// across the sleep, so physics keeps real time under any cap.
+type frameLimiter struct {
+	limit     int
+	nextFrame float64
+}
+
+// newFrameLimiter applies a frame cap setting. Needs the GL context current.
+func newFrameLimiter(limit int) *frameLimiter {
+	f := &frameLimiter{}
+	f.Set(limit)
+	return f
+}
+
+// Set changes the mode, VSync only syncs to the display in VSync mode
+func (f *frameLimiter) Set(limit int) {
+	if limit < frameCapUncapped {
+		limit = frameCapVSync
+	}
+	f.limit = limit
+	f.nextFrame = 0
+	if limit == frameCapVSync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+}
+
+// Limit is the current setting value
+func (f *frameLimiter) Limit() int {
+	return f.limit
+}
+
+// Next switches to the next mode in frameCapSteps
+func (f *frameLimiter) Next() {
+	for i, step := range frameCapSteps {
+		if step == f.limit {
+			f.Set(frameCapSteps[(i+1)%len(frameCapSteps)])
+			return
+		}
+	}
+	// A custom cap from the settings file moves on to the next larger step
+	for _, step := range frameCapSteps {
+		if step > f.limit {
+			f.Set(step)
+			return
+		}
+	}
+	f.Set(frameCapUncapped)
+}
+
+// Wait sleeps until the next frame is due. Call once per frame after
+// swapping buffers.
+func (f *frameLimiter) Wait() {
+	if f.limit <= 0 {
+		return
+	}
+	frameTime := 1 / float64(f.limit)
+	now := glfw.GetTime()
+	// Catch up rather than rush out a burst of frames after a hitch
+	if f.nextFrame == 0 || now-f.nextFrame > frameTime {
+		f.nextFrame = now
+	}
+	f.nextFrame += frameTime
+
+	// Sleep is coarse, wake up early and spin for the last stretch
+	const spinTime = 0.002
+	if remaining := f.nextFrame - now - spinTime; remaining > 0 {
+		time.Sleep(time.Duration(remaining * float64(time.Second)))
+	}
+	for glfw.GetTime() < f.nextFrame {
+	}
+}
+
+func (f *frameLimiter) String() string {
+	switch f.limit {
+	case frameCapVSync:
+		return "VSync"
+	case frameCapUncapped:
+		return "Uncapped"
+	default:
+		return fmt.Sprintf("%d FPS cap", f.limit)
+	}
+}
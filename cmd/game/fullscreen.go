@@ -0,0 +1,74 @@
+package main
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// windowMode switches the window between windowed and borderless
+// fullscreen, remembering where the window was to put it back. The
+// framebuffer size callback picks up the new size either way.
+type windowMode struct {
+	window     *glfw.Window
+	fullscreen bool
+
+	// Windowed position and size, kept while fullscreen
+	x, y          int
+	width, height int
+}
+
+func newWindowMode(window *glfw.Window) *windowMode {
+	m := &windowMode{window: window}
+	m.x, m.y = window.GetPos()
+	m.width, m.height = window.GetSize()
+	return m
+}
+
+// SetFullscreen covers the monitor the window is on at its current video
+// mode, so the display doesn't change resolution, or goes back to the
+// window it was
+func (m *windowMode) SetFullscreen(fullscreen bool) {
+	if fullscreen == m.fullscreen {
+		return
+	}
+	if fullscreen {
+		m.x, m.y = m.window.GetPos()
+		m.width, m.height = m.window.GetSize()
+		monitor := m.monitor()
+		mode := monitor.GetVideoMode()
+		m.window.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+	} else {
+		m.window.SetMonitor(nil, m.x, m.y, m.width, m.height, 0)
+	}
+	m.fullscreen = fullscreen
+}
+
+func (m *windowMode) Toggle() {
+	m.SetFullscreen(!m.fullscreen)
+}
+
+func (m *windowMode) Fullscreen() bool {
+	return m.fullscreen
+}
+
+// WindowedSize is the size of the window when not fullscreen
+func (m *windowMode) WindowedSize() (width, height int) {
+	if !m.fullscreen {
+		m.width, m.height = m.window.GetSize()
+	}
+	return m.width, m.height
+}
+
+// monitor is the one holding the center of the window, the primary monitor
+// if none does
+func (m *windowMode) monitor() *glfw.Monitor {
+	x, y := m.window.GetPos()
+	width, height := m.window.GetSize()
+	centerX, centerY := x+width/2, y+height/2
+
+	for _, monitor := range glfw.GetMonitors() {
+		mode := monitor.GetVideoMode()
+		mx, my := monitor.GetPos()
+		if centerX >= mx && centerX < mx+mode.Width && centerY >= my && centerY < my+mode.Height {
+			return monitor
+		}
+	}
+	return glfw.GetPrimaryMonitor()
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"math"
+	"path/filepath"
 	"runtime"
+	"time"
 
+	"voxel-game/internal/audio"
 	"voxel-game/internal/camera"
+	"voxel-game/internal/config"
+	"voxel-game/internal/errs"
 	"voxel-game/internal/input"
 	"voxel-game/internal/player"
 	"voxel-game/internal/render"
@@ -18,9 +27,15 @@ import (
 )
 
 const (
-	windowWidth  = 1280
-	windowHeight = 720
-	windowTitle  = "Voxel Game"
+	windowTitle = "Voxel Game"
+
+	// Cubes thrown out of each broken block
+	breakParticleCount = 16
+
+	screenshotDir = "screenshots"
+
+	// How long toggles and selections are confirmed for
+	quickNotification = 1500 * time.Millisecond
 )
 
 var memStats runtime.MemStats
@@ -31,6 +46,24 @@ func init() {
 }
 
 func main() {
+	seedFlag := flag.Int64("seed", 0, "seed for a new world (random if not set)")
+	flag.BoolVar(&ui.Debug, "debug-ui", false, "print font metrics and shader details while the UI loads")
+	flag.Parse()
+
+	// Fall back to a time-based seed so every new world is different
+	seed := time.Now().UnixNano()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seed = *seedFlag
+		}
+	})
+
+	// Load settings
+	settings, err := config.Load(config.DefaultPath)
+	if err != nil {
+		log.Println("Using default settings:", err)
+	}
+
 	// Initialize GLFW
 	if err := glfw.Init(); err != nil {
 		log.Fatalln("failed to initialize glfw:", err)
@@ -43,16 +76,23 @@ func main() {
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	// Must be set before the window exists, changing it means recreating the window
+	glfw.WindowHint(glfw.Samples, settings.MSAASamples)
 
-	// Create window
+	// Create window. Everything below starts out sized for the windowed
+	// size, a resize to the real framebuffer follows once the UI exists.
+	windowWidth, windowHeight := settings.WindowWidth, settings.WindowHeight
 	window, err := glfw.CreateWindow(windowWidth, windowHeight, windowTitle, nil, nil)
 	if err != nil {
 		log.Fatalln("failed to create window:", err)
 	}
 	window.MakeContextCurrent()
 
-	// Control VSync
-	glfw.SwapInterval(1) // 1 = VSync on, 0 = VSync off
+	displayMode := newWindowMode(window)
+	displayMode.SetFullscreen(settings.Fullscreen)
+
+	// VSync, uncapped or a fixed frame rate
+	limiter := newFrameLimiter(settings.FrameCap)
 
 	// Initialize OpenGL
 	if err := gl.Init(); err != nil {
@@ -64,6 +104,13 @@ func main() {
 	gl.Enable(gl.CULL_FACE)
 	gl.CullFace(gl.BACK)
 
+	if settings.MSAASamples > 0 {
+		gl.Enable(gl.MULTISAMPLE)
+		var samples int32
+		gl.GetIntegerv(gl.SAMPLES, &samples)
+		log.Printf("MSAA: %dx", samples)
+	}
+
 	gl.Enable(gl.BLEND)
 	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
 
@@ -73,31 +120,61 @@ func main() {
 	version := gl.GoStr(gl.GetString(gl.VERSION))
 	log.Println("OpenGL version:", version)
 
-	// Load Fonts
+	// Load assets, collecting every failure so one run reports them all
+	var assetErrs []error
+
 	pixelFont, err := ui.LoadFont("assets/fonts/MinecraftTen-VGORe.ttf", 24, false)
 	if err != nil {
-		log.Fatalf("Failed to load font: %v. Make sure assets/fonts/MinecraftTen-VGORe.ttf exists!", err)
+		assetErrs = append(assetErrs, err)
 	}
 	cleanFont, err := ui.LoadFont("assets/fonts/MinecraftTen-VGORe.ttf", 24, true)
 	if err != nil {
-		log.Fatalf("Failed to load font: %v. Make sure assets/fonts/MinecraftTen-VGORe.ttf exists!", err)
+		assetErrs = append(assetErrs, err)
 	}
 
 	// Load Texture Atlas (World)
 	atlas, err := render.LoadTexture("assets/atlas.png")
 	if err != nil {
-		log.Fatalf("Failed to load texture atlas: %v", err)
+		assetErrs = append(assetErrs, err)
+	}
+
+	if err := errors.Join(assetErrs...); err != nil {
+		var assetErr *errs.AssetLoadError
+		if errors.As(err, &assetErr) {
+			log.Println("Run the game from the repository root so assets/ can be found")
+		}
+		log.Fatalf("Failed to load assets:\n%v", err)
 	}
 	log.Printf("Loaded atlas.png (ID: %d)", atlas.ID)
+	defer atlas.Delete()
+	defer pixelFont.Delete()
+	defer cleanFont.Delete()
 
 	// Initialize camera
 	cam := camera.NewCamera(windowWidth, windowHeight)
+	cam.SetSensitivity(settings.MouseSensitivity)
+	cam.InvertY = settings.InvertY
+	cam.Smoothing = settings.MouseSmoothing
+	cam.LookCurve = settings.LookCurve
 
 	// Initialize renderer
 	renderer, err := render.NewRenderer()
 	if err != nil {
 		log.Fatalln("failed to create renderer:", err)
 	}
+	defer renderer.Cleanup()
+
+	// The gradient sky is optional, the clear color is the fallback
+	sky, err := render.NewSkyRenderer()
+	if err != nil {
+		log.Println("Warning: gradient sky unavailable:", err)
+	} else {
+		defer sky.Cleanup()
+	}
+
+	renderer.HighlightMode = render.ParseHighlightMode(settings.HighlightMode)
+	renderer.Shadows = settings.Shadows
+	renderer.ShowViewModel = settings.ViewModel
 
 	// Initialize UI renderer
 	uiRenderer, err := ui.NewUIRenderer(windowWidth, windowHeight)
@@ -116,6 +193,18 @@ func main() {
 	debugLayer := ui.NewDebugLayer(cleanFont, windowWidth, windowHeight)
 	uiRenderer.AddElement(debugLayer)
 
+	chunkMap := ui.NewChunkMap(windowWidth, windowHeight)
+	if err := uiRenderer.AddElement(chunkMap); err != nil {
+		log.Fatalln("failed to add chunk map:", err)
+	}
+	var chunkStatuses []world.ChunkStatus
+
+	minimap := ui.NewMinimap(windowWidth, windowHeight)
+	if err := uiRenderer.AddElement(minimap); err != nil {
+		log.Fatalln("failed to add minimap:", err)
+	}
+	var chunkSurfaces []world.BlockType
+
 	crosshair, err := ui.NewCrosshair(windowWidth, windowHeight)
 	if err != nil {
 		log.Fatalln("failed to init crosshair:", err)
@@ -124,13 +213,29 @@ func main() {
 		log.Fatalln("failed to add crosshair:", err)
 	}
 
-	hotbar := ui.NewHotbar(windowWidth, windowHeight)
+	hotbar := ui.NewHotbar(pixelFont, atlas.ID, windowWidth, windowHeight, player.HotbarSize)
 	if err := uiRenderer.AddElement(hotbar); err != nil {
 		log.Fatalln("failed to add hotbar:", err)
 	}
 
-	// Window resize callback
-	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+	healthBar := ui.NewHealthBar(windowWidth, windowHeight)
+	if err := uiRenderer.AddElement(healthBar); err != nil {
+		log.Fatalln("failed to add health bar:", err)
+	}
+
+	console := ui.NewConsole(cleanFont, windowWidth, windowHeight)
+	if err := uiRenderer.AddElement(console); err != nil {
+		log.Fatalln("failed to add console:", err)
+	}
+
+	// Pause menu, added last so it draws over the rest of the HUD
+	menu := ui.NewMenu(pixelFont, windowWidth, windowHeight)
+	if err := uiRenderer.AddElement(menu); err != nil {
+		log.Fatalln("failed to add pause menu:", err)
+	}
+
+	// Window resize, also from switching to or from fullscreen
+	resize := func(width, height int) {
 		gl.Viewport(0, 0, int32(width), int32(height))
 		cam.SetSize(width, height)
 		const targetUIHeight = 720.0
@@ -145,18 +250,105 @@ func main() {
 		//notifications.Update(nil)
 		crosshair.Update(screenSize)
 		hotbar.Update(screenSize)
+		chunkMap.Update(screenSize)
+		minimap.Update(screenSize)
+		healthBar.Update(screenSize)
+		console.Update(screenSize)
+		menu.Update(screenSize)
+	}
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		resize(width, height)
 	})
+	resize(window.GetFramebufferSize())
 
 	// Initialize world
-	gameWorld := world.NewWorld()
+	gameWorld := world.NewWorld(seed)
+	// An existing save takes over, including its seed. One that can't be
+	// read is left as it is for the player to recover, the new world
+	// playing in its place isn't saved over it.
+	saveWorld := true
+	if err := gameWorld.Load(settings.WorldDir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Println("Failed to load world, starting a new one that won't be saved:", err)
+		saveWorld = false
+	}
+	log.Println("World seed:", gameWorld.Seed())
+	gameWorld.SetWorkerCount(settings.ChunkWorkers)
+	gameWorld.UseGreedyMeshing = settings.GreedyMeshing
+	gameWorld.ChunkBudget = settings.ChunksPerFrame
+	defer gameWorld.Close()
+	defer gameWorld.Cleanup()
+	if !settings.OrphanChunkBuffers {
+		gameWorld.SetMeshUploadMode(world.UploadReplace)
+	}
+
+	var distanceTuner *renderDistanceTuner
+	if settings.RenderDistance > 0 {
+		gameWorld.SetRenderDistance(settings.RenderDistance)
+		log.Printf("Render distance: %d chunks", gameWorld.RenderDistance())
+	} else {
+		distanceTuner = newRenderDistanceTuner(gameWorld)
+	}
+
+	clock := world.NewDayClock(settings.TimeOfDay)
+	clock.Frozen = settings.FreezeTime
+	clock.Speed = settings.TimeSpeed
 
 	// Initialize player
 	p := player.NewPlayer(cam, gameWorld)
+	applyPreset(p, settings.HotbarPresets[settings.ActivePreset])
+	p.OnDeath = func() {
+		notifications.AddWithOptions("You died! Respawned at spawn", ui.NotificationOptions{Duration: 5 * time.Second, Color: ui.WarningNotificationColor})
+	}
+
+	// Break particles are cosmetic, the game runs without them
+	particles, err := render.NewParticleSystem(gameWorld, atlas.ID, settings.MaxParticles)
+	if err != nil {
+		log.Println("Warning: particles unavailable:", err)
+	} else {
+		defer particles.Cleanup()
+		p.OnBlockBroken = func(x, y, z int, broken world.BlockType) {
+			center := mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+			particles.Spawn(center, broken, breakParticleCount)
+		}
+	}
+
+	// Sound is optional too, without an audio device the game runs silent
+	if settings.Sound {
+		sounds, err := audio.NewEngine()
+		if err != nil {
+			log.Println("Warning: sound unavailable:", err)
+		} else {
+			defer sounds.Cleanup()
+			gameWorld.AddBlockChangedListener(func(x, y, z int, old, new world.BlockType) {
+				center := mgl32.Vec3{float32(x) + 0.5, float32(y) + 0.5, float32(z) + 0.5}
+				if new == world.BlockAir {
+					sounds.PlaySound("break/"+world.SoundOf(old), center, cam)
+				} else {
+					sounds.PlaySound("place/"+world.SoundOf(new), center, cam)
+				}
+			})
+			p.OnFootstep = func(pos mgl32.Vec3, ground world.BlockType) {
+				sounds.PlaySound("step/"+world.SoundOf(ground), pos, cam)
+			}
+		}
+	}
 
 	wireframeMode := false
 
 	// Initialize input manager
 	inputMgr := input.NewInputManager(window, cam, p, &wireframeMode)
+	inputMgr.PauseOnFocusLoss = settings.PauseOnFocusLoss
+	inputMgr.OnPlaceDenied = func(reason player.PlaceResult) {
+		crosshair.Flash(mgl32.Vec3{1.0, 0.2, 0.2}, 0.25)
+		// Clicking air is common and self-explanatory, only the flash for that
+		if reason != player.PlaceNoTarget {
+			notifications.AddWithOptions("Can't place: "+reason.String(), ui.NotificationOptions{Duration: 4 * time.Second, Color: ui.WarningNotificationColor})
+		}
+	}
+	inputMgr.OnBreakDenied = func() {
+		crosshair.Flash(mgl32.Vec3{1.0, 0.2, 0.2}, 0.25)
+		notifications.AddWithOptions("Can't break: This area is protected", ui.NotificationOptions{Duration: 4 * time.Second, Color: ui.WarningNotificationColor})
+	}
 
 	// Capture cursor
 	window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
@@ -173,8 +365,70 @@ func main() {
 	lastChunkUpdate := glfw.GetTime()
 	chunkUpdateInterval := 0.5
 
-	// Track selected block for hotbar
-	var lastSelectedBlock world.BlockType = world.BlockAir
+	// Last hotbar contents handed to the UI, kept across frames and
+	// refilled in place
+	var hotbarView ui.HotbarState
+
+	inputMgr.RegisterAction("CYCLE_PRESET", glfw.KeyH)
+	inputMgr.RegisterAction("CYCLE_HIGHLIGHT", glfw.KeyO)
+	inputMgr.RegisterAction("FREEZE_TIME", glfw.KeyK)
+	inputMgr.RegisterAction("NEXT_TIME", glfw.KeyT)
+	inputMgr.RegisterAction("VIEW_FARTHER", glfw.KeyEqual)
+	inputMgr.RegisterAction("VIEW_NEARER", glfw.KeyMinus)
+	inputMgr.RegisterAction("FULLSCREEN", glfw.KeyF11)
+	if err := inputMgr.LoadBindings(input.DefaultBindingsPath); err != nil {
+		log.Println("Using default key bindings:", err)
+	}
+
+	// Pause menu pages. Settings rebuilds its page after each change so the
+	// labels show the new values.
+	var showPauseMenu, showSettingsMenu func()
+	showPauseMenu = func() {
+		menu.SetPage("Paused", []ui.MenuButton{
+			{Label: "Resume", OnClick: func() { inputMgr.SetMenuOpen(false) }},
+			{Label: "Settings", OnClick: showSettingsMenu},
+			{Label: "Quit", OnClick: func() { window.SetShouldClose(true) }},
+		})
+	}
+	showSettingsMenu = func() {
+		invert := "Off"
+		if cam.InvertY {
+			invert = "On"
+		}
+		menu.SetPage("Settings", []ui.MenuButton{
+			{Label: "Invert mouse: " + invert, OnClick: func() {
+				cam.InvertY = !cam.InvertY
+				showSettingsMenu()
+			}},
+			{Label: "Frame rate: " + limiter.String(), OnClick: func() {
+				limiter.Next()
+				settings.FrameCap = limiter.Limit()
+				showSettingsMenu()
+			}},
+			{Label: "Highlight: " + renderer.HighlightMode.String(), OnClick: func() {
+				renderer.HighlightMode = renderer.HighlightMode.Next()
+				settings.HighlightMode = renderer.HighlightMode.String()
+				showSettingsMenu()
+			}},
+			{Label: "Back", OnClick: showPauseMenu},
+		})
+	}
+	inputMgr.OnMenuCursor = func(x, y float64) {
+		width, height := window.GetSize()
+		uiRenderer.HandleCursor(x, y, width, height)
+	}
+	inputMgr.OnMenuClick = func(x, y float64, pressed bool) {
+		width, height := window.GetSize()
+		uiRenderer.HandleClick(x, y, pressed, width, height)
+	}
+
+	// Developer console
+	commands := newConsoleCommands(gameWorld, p, clock)
+	console.Print("Type help for a list of commands")
+	inputMgr.OnConsoleChar = console.AddChar
+	inputMgr.OnConsoleKey = func(key glfw.Key) {
+		handleConsoleKey(console, commands, key)
+	}
 
 	// Game loop
 	for !window.ShouldClose() {
@@ -190,76 +444,248 @@ func main() {
 			currentFPS = float64(frameCount) / (currentTime - fpsTime)
 			frameCount = 0
 			fpsTime = currentTime
-		}
 
-		//Memory Stats (every 10 frames)
-		if frameCount%10 == 0 {
-			runtime.ReadMemStats(&memStats)
+			// ReadMemStats stops the world, so only once a second, and only
+			// while the debug HUD shows it
+			if debugLayer.IsVisible() {
+				runtime.ReadMemStats(&memStats)
+			}
+
+			// Remember the detected distance so detection only runs once
+			if distanceTuner != nil && distanceTuner.Sample(currentFPS, gameWorld) {
+				settings.RenderDistance = gameWorld.RenderDistance()
+				distanceTuner = nil
+			}
 		}
 
 		// Handle input
 		inputMgr.Update(deltaTime)
 
+		// Escape opens the menu on its first page, and closes it from any
+		if inputMgr.IsMenuOpen() && !menu.IsVisible() {
+			showPauseMenu()
+		}
+		menu.SetVisible(inputMgr.IsMenuOpen())
+		console.SetVisible(inputMgr.IsConsoleOpen())
+
 		if inputMgr.IsActionJustPressed("TOGGLE_DEBUG") {
 			// Toggle Persistent HUD
 			isVisible := debugLayer.Toggle()
+			if isVisible {
+				runtime.ReadMemStats(&memStats)
+			}
+			chunkMap.SetVisible(isVisible)
+			// Both sit in the top-right corner
+			minimap.SetVisible(!isVisible)
 
 			// Trigger Transient Notification
 			if isVisible {
-				notifications.Add("Debug Mode: ON")
+				notifications.AddWithOptions("Debug Mode: ON", ui.NotificationOptions{Duration: quickNotification})
 			} else {
-				notifications.Add("Debug Mode: OFF")
+				notifications.AddWithOptions("Debug Mode: OFF", ui.NotificationOptions{Duration: quickNotification})
 			}
 		}
-		if !inputMgr.IsDebugMode() {
+		if inputMgr.IsActionJustPressed("CYCLE_PRESET") {
+			if window.GetKey(glfw.KeyLeftShift) == glfw.Press {
+				// Shift+H overwrites the active preset with the current hotbar
+				active := &settings.HotbarPresets[settings.ActivePreset]
+				*active = capturePreset(p, active.Name)
+				notifications.Add("Saved preset: " + active.Name)
+			} else {
+				// Selection index is kept so the same slot stays selected
+				settings.ActivePreset = (settings.ActivePreset + 1) % len(settings.HotbarPresets)
+				preset := settings.HotbarPresets[settings.ActivePreset]
+				applyPreset(p, preset)
+				notifications.AddWithOptions("Hotbar preset: "+preset.Name, ui.NotificationOptions{Duration: quickNotification})
+			}
+		}
+
+		if inputMgr.IsActionJustPressed("CYCLE_HIGHLIGHT") {
+			renderer.HighlightMode = renderer.HighlightMode.Next()
+			settings.HighlightMode = renderer.HighlightMode.String()
+			notifications.Add("Highlight: " + settings.HighlightMode)
+		}
+
+		if inputMgr.IsActionJustPressed("FREEZE_TIME") {
+			clock.Frozen = !clock.Frozen
+			if clock.Frozen {
+				notifications.Add("Time frozen at " + clock.String())
+			} else {
+				notifications.Add("Time resumed")
+			}
+		}
+		if inputMgr.IsActionJustPressed("NEXT_TIME") {
+			clock.Set(world.NextPreset(clock.TimeOfDay))
+			notifications.Add("Time: " + clock.String())
+		}
+
+		// Manual render distance. Chunks load or unload to match on the next
+		// chunk update, and the choice overrides auto-detection.
+		step := 0
+		if inputMgr.IsActionJustPressed("VIEW_FARTHER") {
+			step++
+		}
+		if inputMgr.IsActionJustPressed("VIEW_NEARER") {
+			step--
+		}
+		if step != 0 {
+			gameWorld.SetRenderDistance(gameWorld.RenderDistance() + step)
+			settings.RenderDistance = gameWorld.RenderDistance()
+			distanceTuner = nil
+			notifications.Add(fmt.Sprintf("Render distance: %d chunks", gameWorld.RenderDistance()))
+		}
+
+		if inputMgr.IsActionJustPressed("FULLSCREEN") {
+			displayMode.Toggle()
+			settings.Fullscreen = displayMode.Fullscreen()
+			// Some platforms drop the swap interval along with the mode
+			limiter.Set(limiter.Limit())
+		}
+
+		if inputMgr.IsActionJustPressed("CYCLE_FRAME_CAP") {
+			limiter.Next()
+			settings.FrameCap = limiter.Limit()
+			notifications.Add("Frame rate: " + limiter.String())
+		}
+
+		// F2 saves a screenshot at the end of the frame, Shift+F2 without
+		// the HUD, highlight or hand
+		screenshot, cleanScreenshot := false, false
+		if inputMgr.IsActionJustPressed("SCREENSHOT") {
+			cleanScreenshot = window.GetKey(glfw.KeyLeftShift) == glfw.Press
+			screenshot = !cleanScreenshot
+		}
+
+		paused := inputMgr.IsPaused()
+		if !paused {
+			clock.Advance(deltaTime)
+		}
+		if !paused && !inputMgr.IsDebugMode() {
+			wasWaiting := p.IsWaitingForTerrain()
 			p.Update(deltaTime)
+			if p.IsWaitingForTerrain() && !wasWaiting {
+				notifications.Add("Loading terrain...")
+			}
 		} else {
 			p.UpdateTarget()
 		}
+		if !paused && particles != nil {
+			particles.Update(deltaTime)
+		}
 
 		// Update world chunks based on player position
-		if currentTime-lastChunkUpdate >= chunkUpdateInterval {
-			gameWorld.UpdateChunks(cam.Position[0], cam.Position[2])
+		if !paused && currentTime-lastChunkUpdate >= chunkUpdateInterval {
+			gameWorld.UpdateChunksAsync(cam.Position[0], cam.Position[2])
 			lastChunkUpdate = currentTime
 		}
+		// Mesh whatever the generation workers have finished
+		gameWorld.IntegrateReadyChunks()
 
-		// Update hotbar if selected block changed
-		selectedBlock := inputMgr.GetSelectedBlock()
-		if selectedBlock != lastSelectedBlock {
-			hotbar.Update(selectedBlock)
-			lastSelectedBlock = selectedBlock
+		crosshair.Tick(deltaTime)
+
+		// Hotbar only regenerates geometry when slots or selection changed
+		if refreshHotbarState(&hotbarView, p, inputMgr.GetSelectedSlot()) {
+			hotbar.Update(hotbarView)
 		}
+		// Creative players can't be hurt, so no hearts
+		healthBar.SetVisible(p.Mode == player.Survival)
+		healthBar.Update(ui.HealthState{Health: p.Health, MaxHealth: player.MaxHealth})
+
+		// Sky and sun follow the clock, frozen or not
+		skyColor := clock.SkyColor()
+		gl.ClearColor(skyColor[0], skyColor[1], skyColor[2], 1.0)
+		renderer.SkyColor = skyColor
+		renderer.SunDirection = clock.SunDirection()
+		renderer.SunStrength = clock.Daylight()
+		renderer.AmbientColor = clock.AmbientColor()
 
 		// Clear screen
 		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+		if sky != nil {
+			sky.DrawSky(cam, clock)
+		}
 
-		// Render world
+		// Render world. The frustum follows the camera after this frame's
+		// movement, not just mouse look.
+		renderer.CullFaces = inputMgr.CullFaces()
+		cam.UpdateFrustum()
 		renderStats := renderer.RenderWorld(gameWorld, cam, atlas.ID)
 
+		if particles != nil {
+			// Lit like the terrain ambient plus the sun, so they dim at night
+			particles.Light = renderer.AmbientColor.Add(mgl32.Vec3{1, 1, 1}.Mul(renderer.SunStrength * 0.7))
+			particles.Draw(cam)
+		}
+
+		if cleanScreenshot {
+			saveScreenshot(window, notifications)
+		}
+
 		// Render block highlight
 		target := p.TargetBlock()
-		targetInfo := "Air" // Default text
+		targetInfo := "none"
 		if target.Hit {
+			renderer.DrawBlockHighlight(target.Pos, target.Face, cam, mgl32.Vec3{1.0, 1.0, 1.0})
+			targetInfo = fmt.Sprintf("%s (%.0f, %.0f, %.0f) face:%s",
+				world.GetBlockDef(target.Type).Name,
+				target.Pos[0], target.Pos[1], target.Pos[2],
+				player.FaceName(target.Face))
+		}
+
+		// The free camera isn't attached to the player, so no hand there
+		if !inputMgr.IsDebugMode() {
+			renderer.DrawViewModel(render.ViewModelState{
+				Block:     inputMgr.GetSelectedBlock(),
+				WalkPhase: p.WalkPhase(),
+				Swing:     p.SwingProgress(),
+			}, cam, atlas.ID)
+		}
 
-			renderer.DrawBlockHighlight(target.Pos, cam, mgl32.Vec3{1.0, 1.0, 1.0})
-			targetInfo = fmt.Sprintf("Hit [%.0f, %.0f, %.0f]", target.Pos[0], target.Pos[1], target.Pos[2])
-		}
-
-		debugLayer.UpdateInfo(
-			currentFPS,
-			deltaTime,
-			cam.Position,
-			cam.Front,
-			int(cam.Position[0])>>4,
-			int(cam.Position[2])>>4,
-			memStats.Alloc/1024/1024, // Bytes to MB
-			runtime.NumGoroutine(),
-			renderStats.ChunksRendered, // From RenderWorld
-			renderStats.TotalVertices,  // From RenderWorld
-			targetInfo,                 // From TargetBlock logic
-		)
+		camChunkX, camChunkZ := world.ChunkOf(cam.Position[0], cam.Position[2])
+		particleCount := 0
+		if particles != nil {
+			particleCount = particles.Count()
+		}
+		debugLayer.UpdateInfo(ui.DebugInfo{
+			FPS:            currentFPS,
+			FrameTime:      deltaTime,
+			FrameMode:      limiter.String(),
+			Position:       cam.Position,
+			Facing:         cam.Front,
+			ChunkX:         camChunkX,
+			ChunkZ:         camChunkZ,
+			RenderDistance: gameWorld.RenderDistance(),
+			Biome:          gameWorld.BiomeAt(int(math.Floor(float64(cam.Position[0]))), int(math.Floor(float64(cam.Position[2])))).String(),
+			MemMB:          memStats.Alloc / 1024 / 1024, // Bytes to MB
+			Goroutines:     runtime.NumGoroutine(),
+			Particles:      particleCount,
+			Target:         targetInfo,
+			CullFaces:      renderer.CullFaces,
+			TimeOfDay:      clockText(clock),
+			Seed:           gameWorld.Seed(),
+		}, renderStats)
 		debugLayer.Update(nil)
+
+		// Chunk state grid, only gathered while the debug HUD is up.
+		// Two extra rings show chunks about to load or unload.
+		if debugLayer.IsVisible() {
+			radius := gameWorld.RenderDistance() + 2
+			chunkStatuses = gameWorld.ChunkStatusGrid(camChunkX, camChunkZ, radius, chunkStatuses)
+			chunkMap.Update(ui.ChunkMapState{Radius: radius, Cells: chunkStatuses})
+		} else {
+			radius := gameWorld.RenderDistance()
+			chunkSurfaces = gameWorld.ChunkSurfaceGrid(camChunkX, camChunkZ, radius, chunkSurfaces)
+			minimap.Update(ui.MinimapState{
+				Radius:  radius,
+				Cells:   chunkSurfaces,
+				OffsetX: (cam.Position[0] - float32(camChunkX*world.ChunkSize)) / world.ChunkSize,
+				OffsetZ: (cam.Position[2] - float32(camChunkZ*world.ChunkSize)) / world.ChunkSize,
+				Facing:  cam.Front,
+			})
+		}
 		notifications.Update(nil)
+		console.Update(nil)
+		menu.Update(nil)
 
 		gl.Disable(gl.DEPTH_TEST)
 		gl.DepthMask(false)
@@ -269,11 +695,58 @@ func main() {
 		// Render UI
 		uiRenderer.Render()
 
+		if screenshot {
+			saveScreenshot(window, notifications)
+		}
+
 		gl.Enable(gl.CULL_FACE)
 		gl.DepthMask(true)
 		gl.Enable(gl.DEPTH_TEST)
 
 		// Swap buffers and poll events
 		window.SwapBuffers()
+		limiter.Wait()
+	}
+
+	if saveWorld {
+		if err := gameWorld.Save(settings.WorldDir); err != nil {
+			log.Println("Failed to save world:", err)
+		}
+	}
+
+	settings.TimeOfDay = clock.TimeOfDay
+	settings.WindowWidth, settings.WindowHeight = displayMode.WindowedSize()
+	settings.Fullscreen = displayMode.Fullscreen()
+	settings.FreezeTime = clock.Frozen
+	settings.MouseSensitivity = cam.MouseSensitivity
+	settings.InvertY = cam.InvertY
+	settings.MouseSmoothing = cam.Smoothing
+	settings.LookCurve = cam.LookCurve
+	if err := settings.Save(config.DefaultPath); err != nil {
+		log.Println("Failed to save settings:", err)
+	}
+	if err := inputMgr.SaveBindings(input.DefaultBindingsPath); err != nil {
+		log.Println("Failed to save key bindings:", err)
+	}
+}
+
+// saveScreenshot writes what has been drawn so far this frame to a
+// timestamped PNG in screenshotDir
+func saveScreenshot(window *glfw.Window, notifications *ui.NotificationSystem) {
+	width, height := window.GetFramebufferSize()
+	path := filepath.Join(screenshotDir, time.Now().Format("2006-01-02_15-04-05.000")+".png")
+	if err := render.CaptureScreenshot(width, height, path); err != nil {
+		log.Println("Failed to save screenshot:", err)
+		notifications.AddWithOptions("Screenshot failed", ui.NotificationOptions{Duration: 4 * time.Second, Color: ui.WarningNotificationColor})
+		return
+	}
+	notifications.Add("Saved " + path)
+}
+
+// clockText is the debug overlay's time readout
+func clockText(clock *world.DayClock) string {
+	if clock.Frozen {
+		return clock.String() + " (frozen)"
 	}
+	return clock.String()
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"log"
+
+	"voxel-game/internal/config"
+	"voxel-game/internal/player"
+	"voxel-game/internal/ui"
+	"voxel-game/internal/world"
+)
+
+// applyPreset loads a hotbar preset into the player's inventory
+func applyPreset(p *player.Player, preset config.HotbarPreset) {
+	stacks := make([]player.ItemStack, 0, len(preset.Slots))
+	for _, slot := range preset.Slots {
+		blockType, ok := world.BlockByName(slot.Block)
+		if !ok || slot.Count <= 0 {
+			if slot.Block != "" {
+				log.Printf("Hotbar preset %q: unknown block %q", preset.Name, slot.Block)
+			}
+			stacks = append(stacks, player.ItemStack{})
+			continue
+		}
+		stacks = append(stacks, player.ItemStack{Type: blockType, Count: slot.Count})
+	}
+	p.Inventory.SetHotbar(stacks)
+}
+
+// capturePreset snapshots the player's current hotbar as a preset
+func capturePreset(p *player.Player, name string) config.HotbarPreset {
+	preset := config.HotbarPreset{Name: name}
+	for _, stack := range p.Inventory.Hotbar() {
+		slot := config.PresetSlot{}
+		if stack.Count > 0 {
+			slot.Block = world.GetBlockDef(stack.Type).Name
+			slot.Count = stack.Count
+		}
+		preset.Slots = append(preset.Slots, slot)
+	}
+	return preset
+}
+
+// refreshHotbarState copies the inventory's hotbar and the selected slot
+// into state, reusing its slices, and reports whether anything changed so
+// the hotbar is only updated when it has to be
+func refreshHotbarState(state *ui.HotbarState, p *player.Player, selected int) bool {
+	hotbar := p.Inventory.Hotbar()
+	changed := state.Selected != selected || len(state.Slots) != len(hotbar)
+	if len(state.Slots) != len(hotbar) {
+		state.Slots = make([]world.BlockType, len(hotbar))
+		state.Counts = make([]int, len(hotbar))
+	}
+	state.Selected = selected
+	for i, stack := range hotbar {
+		var blockType world.BlockType
+		if stack.Count > 0 {
+			blockType = stack.Type
+		}
+		if state.Slots[i] != blockType || state.Counts[i] != stack.Count {
+			state.Slots[i] = blockType
+			state.Counts[i] = stack.Count
+			changed = true
+		}
+	}
+	return changed
+}
@@ -3,6 +3,7 @@ module voxel-game
 go 1.24.0
 
 require (
+	github.com/ebitengine/oto/v3 v3.4.0
 	github.com/go-gl/gl v0.0.0-20231021071112-07e5d0ea2e71
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20240506104042-037f3cc74f2a
 	github.com/go-gl/mathgl v1.1.0
@@ -10,3 +11,8 @@ require (
 	github.com/ojrac/opensimplex-go v1.0.2
 	golang.org/x/image v0.35.0
 )
+
+require (
+	github.com/ebitengine/purego v0.9.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+)
@@ -0,0 +1,124 @@
+// Package audio plays short positional sound effects. The sounds are
+// synthesized at startup (see synth.go), so there are no sound assets.
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"voxel-game/internal/camera"
+
+	"github.com/ebitengine/oto/v3"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	sampleRate = 44100
+
+	// Sounds fade out completely at this distance from the listener, in blocks
+	maxHearDistance = 24.0
+	// Most sounds playing at once, new ones are dropped past this
+	maxVoices = 32
+)
+
+type Engine struct {
+	ctx     *oto.Context
+	sounds  map[string][]float32 // Mono samples in [-1, 1]
+	playing []*oto.Player
+
+	// Master volume, 0 to 1
+	Volume float32
+}
+
+// NewEngine opens the audio device and synthesizes the sound set. Fails if
+// there is no audio device; the game should carry on without sound.
+func NewEngine() (*Engine, error) {
+	ctx, ready, err := oto.NewContext(&oto.NewContextOptions{
+		SampleRate:   sampleRate,
+		ChannelCount: 2,
+		Format:       oto.FormatSignedInt16LE,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not open audio device: %w", err)
+	}
+	<-ready
+
+	return &Engine{
+		ctx:    ctx,
+		sounds: synthesizeSounds(),
+		Volume: 1,
+	}, nil
+}
+
+// PlaySound plays a sound named "<event>/<material>", e.g. "break/stone",
+// at a world position. It gets quieter with distance from the listener and
+// is panned toward the side it comes from. Unknown materials fall back to
+// stone, unknown events are ignored.
+func (e *Engine) PlaySound(name string, pos mgl32.Vec3, listener *camera.Camera) {
+	samples, ok := e.sounds[name]
+	if !ok {
+		event, _, _ := strings.Cut(name, "/")
+		if samples, ok = e.sounds[event+"/stone"]; !ok {
+			return
+		}
+	}
+
+	offset := pos.Sub(listener.Position)
+	distance := offset.Len()
+	if distance >= maxHearDistance {
+		return
+	}
+	fade := 1 - distance/maxHearDistance
+	gain := e.Volume * fade * fade
+
+	// Equal power panning, scaled so a sound straight ahead plays at full
+	// volume in both ears
+	pan := float32(0)
+	if distance > 0.001 {
+		pan = offset.Mul(1 / distance).Dot(listener.Right)
+	}
+	angle := float64(pan+1) * math.Pi / 4
+	left := gain * min(1, float32(math.Cos(angle)*math.Sqrt2))
+	right := gain * min(1, float32(math.Sin(angle)*math.Sqrt2))
+
+	e.prune()
+	if len(e.playing) >= maxVoices {
+		return
+	}
+
+	pcm := make([]byte, len(samples)*4)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(toInt16(sample*left)))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(toInt16(sample*right)))
+	}
+	player := e.ctx.NewPlayer(bytes.NewReader(pcm))
+	player.Play()
+	e.playing = append(e.playing, player)
+}
+
+// prune closes players that have finished
+func (e *Engine) prune() {
+	active := e.playing[:0]
+	for _, player := range e.playing {
+		if player.IsPlaying() {
+			active = append(active, player)
+		} else {
+			player.Close()
+		}
+	}
+	e.playing = active
+}
+
+func (e *Engine) Cleanup() {
+	for _, player := range e.playing {
+		player.Close()
+	}
+	e.playing = nil
+}
+
+func toInt16(sample float32) int16 {
+	return int16(max(-1, min(1, sample)) * math.MaxInt16)
+}
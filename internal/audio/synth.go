@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"math"
+	"math/rand"
+)
+
+// material shapes the noise burst every sound of a block type is made of
+type material struct {
+	cutoff float64 // Low-pass strength, 0 to 1, lower is duller
+	tone   float64 // Frequency of a pitched knock under the noise, 0 for none
+	mix    float64 // How much of the knock is heard
+}
+
+var materials = map[string]material{
+	"stone": {cutoff: 0.55, tone: 320, mix: 0.25},
+	"dirt":  {cutoff: 0.12, tone: 90, mix: 0.3},
+	"grass": {cutoff: 0.3},
+	"sand":  {cutoff: 0.4},
+	"snow":  {cutoff: 0.2},
+	"wood":  {cutoff: 0.2, tone: 180, mix: 0.6},
+	"glass": {cutoff: 0.8, tone: 1400, mix: 0.4},
+}
+
+// event is how long and loud each kind of sound is
+type event struct {
+	duration float64 // Seconds
+	decay    float64 // Envelope time constant, seconds
+	gain     float64
+}
+
+var events = map[string]event{
+	"break": {duration: 0.3, decay: 0.08, gain: 0.8},
+	"place": {duration: 0.15, decay: 0.035, gain: 0.6},
+	"step":  {duration: 0.12, decay: 0.03, gain: 0.25},
+}
+
+// synthesizeSounds renders every event for every material. Each is filtered
+// noise under a decaying envelope, with an optional pitched knock.
+func synthesizeSounds() map[string][]float32 {
+	rng := rand.New(rand.NewSource(1))
+	sounds := make(map[string][]float32, len(events)*len(materials))
+
+	for eventName, ev := range events {
+		for materialName, mat := range materials {
+			samples := make([]float32, int(ev.duration*sampleRate))
+			filtered := 0.0
+			for i := range samples {
+				t := float64(i) / sampleRate
+				filtered += mat.cutoff * (rng.Float64()*2 - 1 - filtered)
+				knock := math.Sin(2 * math.Pi * mat.tone * t)
+				sample := filtered*(1-mat.mix) + knock*mat.mix
+				samples[i] = float32(sample * math.Exp(-t/ev.decay))
+			}
+			normalize(samples, ev.gain)
+			sounds[eventName+"/"+materialName] = samples
+		}
+	}
+	return sounds
+}
+
+// normalize scales samples so the loudest peak is at gain. Heavily filtered
+// noise is much quieter than the raw noise it came from.
+func normalize(samples []float32, gain float64) {
+	peak := float32(0)
+	for _, sample := range samples {
+		peak = max(peak, float32(math.Abs(float64(sample))))
+	}
+	if peak == 0 {
+		return
+	}
+	scale := float32(gain) / peak
+	for i := range samples {
+		samples[i] *= scale
+	}
+}
@@ -6,6 +6,13 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+const (
+	DefaultPitchLimit = 89.0
+	FullPitchLimit    = 90.0
+
+	DefaultSensitivity = 0.1
+)
+
 type Camera struct {
 	Position mgl32.Vec3
 	Front    mgl32.Vec3
@@ -16,9 +23,25 @@ type Camera struct {
 	Yaw   float32
 	Pitch float32
 
+	// Pitch clamp in degrees. Gameplay keeps a margin from straight up/down;
+	// the free camera can use the full range.
+	MinPitch float32
+	MaxPitch float32
+
 	MovementSpeed    float32
 	MouseSensitivity float32
-	Fov              float32
+	// Flip vertical look, moving the mouse up looks down
+	InvertY bool
+	// How much of the previous frames' movement carries into this one, from
+	// 0 (raw input) up to 0.95. Evens out jittery deltas at low frame rates.
+	Smoothing float32
+	// Exponent applied to the mouse speed, 1 is linear. Above 1 slow
+	// movements turn less for fine aiming and fast flicks turn more.
+	LookCurve float32
+	Fov       float32
+
+	// Smoothed movement carried between frames
+	smoothX, smoothY float32
 
 	width  int
 	height int
@@ -35,13 +58,17 @@ func NewCamera(width, height int) *Camera {
 		WorldUp:          mgl32.Vec3{0, 1, 0},
 		Yaw:              -90.0,
 		Pitch:            0.0,
+		MinPitch:         -DefaultPitchLimit,
+		MaxPitch:         DefaultPitchLimit,
 		MovementSpeed:    15.0,
-		MouseSensitivity: 0.1,
+		MouseSensitivity: DefaultSensitivity,
+		LookCurve:        1,
 		Fov:              45.0,
 		width:            width,
 		height:           height,
 	}
 	c.updateCameraVectors()
+	c.updateFrustum()
 	return c
 }
 
@@ -58,24 +85,66 @@ func (c *Camera) GetProjectionMatrix() mgl32.Mat4 {
 	)
 }
 
+// lookCurveReference is the movement per call, in mouse units, that the look
+// curve leaves unchanged. Slower movement is scaled down, faster scaled up.
+const lookCurveReference = 10
+
+// ProcessMouseMovement turns the camera by a frame's worth of mouse movement.
+// Call it once per frame, even with no movement, so smoothing settles.
 func (c *Camera) ProcessMouseMovement(xoffset, yoffset float32) {
+	if c.LookCurve > 0 && c.LookCurve != 1 {
+		if speed := float32(math.Hypot(float64(xoffset), float64(yoffset))); speed > 0 {
+			curved := lookCurveReference * float32(math.Pow(float64(speed/lookCurveReference), float64(c.LookCurve)))
+			xoffset *= curved / speed
+			yoffset *= curved / speed
+		}
+	}
+	if c.Smoothing > 0 {
+		keep := min(c.Smoothing, 0.95)
+		c.smoothX = c.smoothX*keep + xoffset*(1-keep)
+		c.smoothY = c.smoothY*keep + yoffset*(1-keep)
+		xoffset, yoffset = c.smoothX, c.smoothY
+	}
+
 	xoffset *= c.MouseSensitivity
 	yoffset *= c.MouseSensitivity
+	if c.InvertY {
+		yoffset = -yoffset
+	}
 
 	c.Yaw += xoffset
 	c.Pitch += yoffset
 
-	// Constrain pitch
-	if c.Pitch > 89.0 {
-		c.Pitch = 89.0
-	}
-	if c.Pitch < -89.0 {
-		c.Pitch = -89.0
+	c.clampPitch()
+	c.updateCameraVectors()
+}
+
+// SetSensitivity sets degrees turned per unit of mouse movement. Values that
+// would stop or reverse the camera fall back to the default.
+func (c *Camera) SetSensitivity(sensitivity float32) {
+	if sensitivity <= 0 {
+		sensitivity = DefaultSensitivity
 	}
+	c.MouseSensitivity = sensitivity
+}
 
+// SetPitchLimits changes the pitch clamp and re-applies it immediately
+func (c *Camera) SetPitchLimits(min, max float32) {
+	c.MinPitch = min
+	c.MaxPitch = max
+	c.clampPitch()
 	c.updateCameraVectors()
 }
 
+func (c *Camera) clampPitch() {
+	if c.Pitch > c.MaxPitch {
+		c.Pitch = c.MaxPitch
+	}
+	if c.Pitch < c.MinPitch {
+		c.Pitch = c.MinPitch
+	}
+}
+
 func (c *Camera) updateCameraVectors() {
 	// Calculate new Front vector
 	front := mgl32.Vec3{
@@ -85,11 +154,18 @@ func (c *Camera) updateCameraVectors() {
 	}
 	c.Front = front.Normalize()
 
-	// Recalculate Right and Up vectors
-	c.Right = c.Front.Cross(c.WorldUp).Normalize()
+	// Recalculate Right and Up vectors. Right comes from yaw alone: at ±90°
+	// pitch Front is parallel to WorldUp and their cross product is zero,
+	// which would flip or NaN the view.
+	yaw := float64(mgl32.DegToRad(c.Yaw))
+	c.Right = mgl32.Vec3{float32(-math.Sin(yaw)), 0, float32(math.Cos(yaw))}
 	c.Up = c.Right.Cross(c.Front).Normalize()
+}
 
-	// Only update frustum if NOT frozen
+// UpdateFrustum recomputes the culling planes from the current position,
+// orientation and size. Call it once per frame before culling; it does
+// nothing while FrustumFrozen is set so the old planes can be inspected.
+func (c *Camera) UpdateFrustum() {
 	if !c.FrustumFrozen {
 		c.updateFrustum()
 	}
@@ -0,0 +1,27 @@
+package camera
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestFrozenFrustum(t *testing.T) {
+	c := NewCamera(1280, 720)
+	c.UpdateFrustum()
+	frozen := c.frustum
+
+	c.FrustumFrozen = true
+	c.Position = c.Position.Add(mgl32.Vec3{100, 20, -50})
+	c.ProcessMouseMovement(300, 100)
+	c.UpdateFrustum()
+	if c.frustum != frozen {
+		t.Error("UpdateFrustum moved the planes while frozen")
+	}
+
+	c.FrustumFrozen = false
+	c.UpdateFrustum()
+	if c.frustum == frozen {
+		t.Error("UpdateFrustum left the planes after unfreezing and moving")
+	}
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const DefaultPath = "settings.json"
+
+// PresetSlot is one hotbar slot in a preset, stored by block name so the
+// file stays readable and survives block enum changes
+type PresetSlot struct {
+	Block string `json:"block"`
+	Count int    `json:"count"`
+}
+
+type HotbarPreset struct {
+	Name  string       `json:"name"`
+	Slots []PresetSlot `json:"slots"`
+}
+
+// Settings is everything persisted between runs
+type Settings struct {
+	HotbarPresets []HotbarPreset `json:"hotbarPresets"`
+	ActivePreset  int            `json:"activePreset"`
+
+	PauseOnFocusLoss bool `json:"pauseOnFocusLoss"`
+
+	// Block highlight style, e.g. "outline", "shade", "face" or "outline+face"
+	HighlightMode string `json:"highlightMode"`
+
+	// Render distance in chunks, 0 to auto-detect from FPS on the next run
+	RenderDistance int `json:"renderDistance"`
+
+	// Cap on live particles, past it new ones evict the oldest
+	MaxParticles int `json:"maxParticles"`
+
+	// Orphan chunk VBOs before re-uploading edited meshes to avoid GPU sync stalls
+	OrphanChunkBuffers bool `json:"orphanChunkBuffers"`
+
+	// Sun shadow map, off by default while it's low quality
+	Shadows bool `json:"shadows"`
+
+	// Degrees turned per unit of mouse movement, and whether moving the
+	// mouse up looks down
+	MouseSensitivity float32 `json:"mouseSensitivity"`
+	InvertY          bool    `json:"invertY"`
+	// Mouse smoothing from 0 (raw) to 0.95, and the look curve exponent, 1
+	// for linear or a little above for finer slow aiming
+	MouseSmoothing float32 `json:"mouseSmoothing"`
+	LookCurve      float32 `json:"lookCurve"`
+
+	// Sound effects, off also skips opening the audio device
+	Sound bool `json:"sound"`
+
+	// Draw the held block (or hand) in first person
+	ViewModel bool `json:"viewModel"`
+
+	// Frame rate limit: 0 syncs to the display (VSync), -1 is uncapped, any
+	// other number caps frames per second
+	FrameCap int `json:"frameCap"`
+
+	// Windowed size, and whether the game starts borderless fullscreen on
+	// the monitor's current resolution. F11 switches and is saved on exit.
+	WindowWidth  int  `json:"windowWidth"`
+	WindowHeight int  `json:"windowHeight"`
+	Fullscreen   bool `json:"fullscreen"`
+
+	// Multisample anti-aliasing: 0 (off), 2, 4 or 8 samples. Read when the
+	// window is created, so changes take effect on the next start.
+	MSAASamples int `json:"msaaSamples"`
+
+	// Chunk generation worker goroutines, 0 for one per CPU
+	ChunkWorkers int `json:"chunkWorkers"`
+	// Most new chunks added to the world per frame. Higher fills the view
+	// faster, lower keeps frame times smoother.
+	ChunksPerFrame int `json:"chunksPerFrame"`
+
+	// Merge flat runs of identical faces into larger quads when meshing
+	GreedyMeshing bool `json:"greedyMeshing"`
+
+	// Directory the world is loaded from at startup and saved to on exit
+	WorldDir string `json:"worldDir"`
+
+	// Time of day in [0, 1), 0 midnight and 0.5 noon. Restored on the next
+	// run; FreezeTime stops it advancing.
+	TimeOfDay  float32 `json:"timeOfDay"`
+	FreezeTime bool    `json:"freezeTime"`
+	// How fast the day passes, 1 is a 10 minute day
+	TimeSpeed float32 `json:"timeSpeed"`
+}
+
+func Default() *Settings {
+	return &Settings{
+		HotbarPresets: []HotbarPreset{
+			{
+				Name: "Default",
+				Slots: []PresetSlot{
+					{Block: "Dirt", Count: 64},
+					{Block: "Grass", Count: 64},
+					{Block: "Stone", Count: 64},
+					{Block: "Snow", Count: 64},
+					{Block: "Sand", Count: 64},
+					{Block: "Wood", Count: 64},
+					{Block: "Lamp", Count: 64},
+					{Block: "Glowstone", Count: 64},
+				},
+			},
+		},
+		PauseOnFocusLoss: true,
+		HighlightMode:    "outline",
+		MaxParticles:     4096,
+
+		OrphanChunkBuffers: true,
+		MouseSensitivity:   0.1,
+		LookCurve:          1,
+		Sound:              true,
+		ViewModel:          true,
+		WindowWidth:        1280,
+		WindowHeight:       720,
+		MSAASamples:        2,
+		ChunksPerFrame:     4,
+		GreedyMeshing:      true,
+		WorldDir:           "world",
+		TimeOfDay:          0.35,
+		TimeSpeed:          1,
+	}
+}
+
+// Load reads settings from path. A missing file is not an error and yields
+// defaults; fields absent from the file keep their default values.
+func Load(path string) (*Settings, error) {
+	s := Default()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("could not read settings: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return Default(), fmt.Errorf("could not parse settings %s: %w", path, err)
+	}
+
+	if len(s.HotbarPresets) == 0 {
+		s.HotbarPresets = Default().HotbarPresets
+	}
+	if s.ActivePreset < 0 || s.ActivePreset >= len(s.HotbarPresets) {
+		s.ActivePreset = 0
+	}
+	// Limits must stay finite, fall back to defaults if unset or nonsensical
+	if s.MaxParticles <= 0 {
+		s.MaxParticles = Default().MaxParticles
+	}
+	if s.MouseSensitivity <= 0 {
+		s.MouseSensitivity = Default().MouseSensitivity
+	}
+	if s.MouseSmoothing < 0 || s.MouseSmoothing > 0.95 {
+		s.MouseSmoothing = Default().MouseSmoothing
+	}
+	if s.LookCurve <= 0 {
+		s.LookCurve = Default().LookCurve
+	}
+	if s.ChunksPerFrame <= 0 {
+		s.ChunksPerFrame = Default().ChunksPerFrame
+	}
+	if s.FrameCap < -1 {
+		s.FrameCap = Default().FrameCap
+	}
+	// Too small a window can't fit the HUD
+	if s.WindowWidth < 320 || s.WindowHeight < 240 {
+		s.WindowWidth, s.WindowHeight = Default().WindowWidth, Default().WindowHeight
+	}
+	switch s.MSAASamples {
+	case 0, 2, 4, 8:
+	default:
+		s.MSAASamples = Default().MSAASamples
+	}
+	// Time can't run backwards, freezing is done with FreezeTime
+	if s.TimeSpeed < 0 {
+		s.TimeSpeed = Default().TimeSpeed
+	}
+
+	return s, nil
+}
+
+func (s *Settings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package errs
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// AssetLoadError is a failure to read or decode a file from disk
+type AssetLoadError struct {
+	Path  string
+	Cause error
+}
+
+func (e *AssetLoadError) Error() string {
+	return fmt.Sprintf("failed to load asset %s: %v", e.Path, e.Cause)
+}
+
+func (e *AssetLoadError) Unwrap() error {
+	return e.Cause
+}
+
+// ShaderCompileError carries the driver's info log. Stage is "vertex",
+// "fragment" or "link".
+type ShaderCompileError struct {
+	Stage string
+	Log   string
+}
+
+func (e *ShaderCompileError) Error() string {
+	return fmt.Sprintf("failed to %s shader: %s", shaderVerb(e.Stage), e.Log)
+}
+
+func shaderVerb(stage string) string {
+	if stage == "link" {
+		return "link"
+	}
+	return "compile " + stage
+}
+
+// GLError is an error flag raised by OpenGL after Op
+type GLError struct {
+	Op   string
+	Code uint32
+}
+
+func (e *GLError) Error() string {
+	return fmt.Sprintf("%s: %d (%s)", e.Op, e.Code, GLErrorString(e.Code))
+}
+
+// CheckGL drains the GL error queue and returns the first error found, if any
+func CheckGL(op string) error {
+	var first error
+	for {
+		code := gl.GetError()
+		if code == gl.NO_ERROR {
+			return first
+		}
+		if first == nil {
+			first = &GLError{Op: op, Code: code}
+		}
+	}
+}
+
+func GLErrorString(code uint32) string {
+	switch code {
+	case gl.INVALID_ENUM:
+		return "INVALID_ENUM"
+	case gl.INVALID_VALUE:
+		return "INVALID_VALUE"
+	case gl.INVALID_OPERATION:
+		return "INVALID_OPERATION"
+	case gl.INVALID_FRAMEBUFFER_OPERATION:
+		return "INVALID_FRAMEBUFFER_OPERATION"
+	case gl.OUT_OF_MEMORY:
+		return "OUT_OF_MEMORY"
+	default:
+		return fmt.Sprintf("UNKNOWN_ERROR_%d", code)
+	}
+}
+
+// SaveFormatError is a world save file that can't be read back, either
+// because it was written by another format version or it is damaged
+type SaveFormatError struct {
+	Path   string
+	Reason string
+}
+
+func (e *SaveFormatError) Error() string {
+	return fmt.Sprintf("unreadable save file %s: %s", e.Path, e.Reason)
+}
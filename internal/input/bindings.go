@@ -0,0 +1,203 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+const DefaultBindingsPath = "controls.json"
+
+// Binding is the keyboard key or mouse button an action is bound to
+type Binding struct {
+	Key    glfw.Key
+	Button glfw.MouseButton
+	Mouse  bool
+}
+
+func KeyBinding(key glfw.Key) Binding {
+	return Binding{Key: key}
+}
+
+func MouseBinding(button glfw.MouseButton) Binding {
+	return Binding{Button: button, Mouse: true}
+}
+
+// Built-in actions, registered by NewInputManager
+var defaultBindings = map[string]Binding{
+	"FORWARD":         KeyBinding(glfw.KeyW),
+	"BACK":            KeyBinding(glfw.KeyS),
+	"LEFT":            KeyBinding(glfw.KeyA),
+	"RIGHT":           KeyBinding(glfw.KeyD),
+	"JUMP":            KeyBinding(glfw.KeySpace),
+	"CROUCH":          KeyBinding(glfw.KeyLeftControl),
+	"SPRINT":          KeyBinding(glfw.KeyLeftShift),
+	"FLY_DOWN":        KeyBinding(glfw.KeyLeftAlt),
+	"TOGGLE_FLY":      KeyBinding(glfw.KeyV),
+	"BREAK":           MouseBinding(glfw.MouseButtonLeft),
+	"PLACE":           MouseBinding(glfw.MouseButtonRight),
+	"PLACE_ALT":       KeyBinding(glfw.KeyB),
+	"TOGGLE_CURSOR":   KeyBinding(glfw.KeyTab),
+	"PAUSE":           KeyBinding(glfw.KeyEscape),
+	"CONSOLE":         KeyBinding(glfw.KeyGraveAccent),
+	"TOGGLE_DEBUG":    KeyBinding(glfw.KeyG),
+	"WIREFRAME":       KeyBinding(glfw.KeyF),
+	"TOGGLE_CULL":     KeyBinding(glfw.KeyC),
+	"TOGGLE_MODE":     KeyBinding(glfw.KeyM),
+	"FREEZE_FRUSTUM":  KeyBinding(glfw.KeyP),
+	"SCREENSHOT":      KeyBinding(glfw.KeyF2),
+	"CYCLE_FRAME_CAP": KeyBinding(glfw.KeyF3),
+	"HOTBAR_1":        KeyBinding(glfw.Key1),
+	"HOTBAR_2":        KeyBinding(glfw.Key2),
+	"HOTBAR_3":        KeyBinding(glfw.Key3),
+	"HOTBAR_4":        KeyBinding(glfw.Key4),
+	"HOTBAR_5":        KeyBinding(glfw.Key5),
+	"HOTBAR_6":        KeyBinding(glfw.Key6),
+	"HOTBAR_7":        KeyBinding(glfw.Key7),
+}
+
+// Names used for keys and buttons in the bindings file
+var bindingNames = func() map[string]Binding {
+	names := map[string]Binding{
+		"SPACE":         KeyBinding(glfw.KeySpace),
+		"ESCAPE":        KeyBinding(glfw.KeyEscape),
+		"APOSTROPHE":    KeyBinding(glfw.KeyApostrophe),
+		"COMMA":         KeyBinding(glfw.KeyComma),
+		"MINUS":         KeyBinding(glfw.KeyMinus),
+		"PERIOD":        KeyBinding(glfw.KeyPeriod),
+		"SLASH":         KeyBinding(glfw.KeySlash),
+		"SEMICOLON":     KeyBinding(glfw.KeySemicolon),
+		"EQUAL":         KeyBinding(glfw.KeyEqual),
+		"LEFT_BRACKET":  KeyBinding(glfw.KeyLeftBracket),
+		"BACKSLASH":     KeyBinding(glfw.KeyBackslash),
+		"RIGHT_BRACKET": KeyBinding(glfw.KeyRightBracket),
+		"GRAVE_ACCENT":  KeyBinding(glfw.KeyGraveAccent),
+		"ENTER":         KeyBinding(glfw.KeyEnter),
+		"TAB":           KeyBinding(glfw.KeyTab),
+		"BACKSPACE":     KeyBinding(glfw.KeyBackspace),
+		"INSERT":        KeyBinding(glfw.KeyInsert),
+		"DELETE":        KeyBinding(glfw.KeyDelete),
+		"RIGHT_ARROW":   KeyBinding(glfw.KeyRight),
+		"LEFT_ARROW":    KeyBinding(glfw.KeyLeft),
+		"DOWN_ARROW":    KeyBinding(glfw.KeyDown),
+		"UP_ARROW":      KeyBinding(glfw.KeyUp),
+		"PAGE_UP":       KeyBinding(glfw.KeyPageUp),
+		"PAGE_DOWN":     KeyBinding(glfw.KeyPageDown),
+		"HOME":          KeyBinding(glfw.KeyHome),
+		"END":           KeyBinding(glfw.KeyEnd),
+		"CAPS_LOCK":     KeyBinding(glfw.KeyCapsLock),
+		"LEFT_SHIFT":    KeyBinding(glfw.KeyLeftShift),
+		"LEFT_CONTROL":  KeyBinding(glfw.KeyLeftControl),
+		"LEFT_ALT":      KeyBinding(glfw.KeyLeftAlt),
+		"RIGHT_SHIFT":   KeyBinding(glfw.KeyRightShift),
+		"RIGHT_CONTROL": KeyBinding(glfw.KeyRightControl),
+		"RIGHT_ALT":     KeyBinding(glfw.KeyRightAlt),
+		"MOUSE_LEFT":    MouseBinding(glfw.MouseButtonLeft),
+		"MOUSE_RIGHT":   MouseBinding(glfw.MouseButtonRight),
+		"MOUSE_MIDDLE":  MouseBinding(glfw.MouseButtonMiddle),
+		"MOUSE_4":       MouseBinding(glfw.MouseButton4),
+		"MOUSE_5":       MouseBinding(glfw.MouseButton5),
+	}
+	for key := glfw.KeyA; key <= glfw.KeyZ; key++ {
+		names[string(rune('A'+key-glfw.KeyA))] = KeyBinding(key)
+	}
+	for key := glfw.Key0; key <= glfw.Key9; key++ {
+		names[strconv.Itoa(int(key-glfw.Key0))] = KeyBinding(key)
+	}
+	for key := glfw.KeyF1; key <= glfw.KeyF12; key++ {
+		names["F"+strconv.Itoa(int(key-glfw.KeyF1)+1)] = KeyBinding(key)
+	}
+	return names
+}()
+
+// String is the binding's name in the bindings file. Keys and buttons
+// without a name are written as KEY_<code> or MOUSE_<number>, which
+// ParseBinding reads back.
+func (b Binding) String() string {
+	for name, other := range bindingNames {
+		if other == b {
+			return name
+		}
+	}
+	if b.Mouse {
+		return fmt.Sprintf("MOUSE_%d", b.Button+1)
+	}
+	return fmt.Sprintf("KEY_%d", b.Key)
+}
+
+// ParseBinding looks up a key or mouse button by its bindings file name,
+// including the KEY_<code> and MOUSE_<number> forms String falls back to
+func ParseBinding(name string) (Binding, bool) {
+	if b, ok := bindingNames[name]; ok {
+		return b, true
+	}
+	if code, ok := strings.CutPrefix(name, "KEY_"); ok {
+		n, err := strconv.Atoi(code)
+		if err != nil || n < 0 || n > int(glfw.KeyLast) {
+			return Binding{}, false
+		}
+		return KeyBinding(glfw.Key(n)), true
+	}
+	if number, ok := strings.CutPrefix(name, "MOUSE_"); ok {
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 || n > int(glfw.MouseButtonLast)+1 {
+			return Binding{}, false
+		}
+		return MouseBinding(glfw.MouseButton(n - 1)), true
+	}
+	return Binding{}, false
+}
+
+// LoadBindings rebinds registered actions from a JSON file mapping action
+// names to key names, e.g. {"JUMP": "SPACE", "BREAK": "MOUSE_LEFT"}. A
+// missing file is not an error. Unknown actions and unknown keys are
+// skipped, leaving those actions on their defaults.
+func (im *InputManager) LoadBindings(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read bindings: %w", err)
+	}
+
+	var names map[string]string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("could not parse bindings %s: %w", path, err)
+	}
+
+	for action, keyName := range names {
+		if _, ok := im.actionBindings[action]; !ok {
+			log.Printf("Bindings: unknown action %q", action)
+			continue
+		}
+		binding, ok := ParseBinding(keyName)
+		if !ok {
+			log.Printf("Bindings: unknown key %q for %s, keeping %s", keyName, action, im.actionBindings[action])
+			continue
+		}
+		im.actionBindings[action] = binding
+	}
+	return nil
+}
+
+// SaveBindings writes every registered action's binding to path
+func (im *InputManager) SaveBindings(path string) error {
+	names := make(map[string]string, len(im.actionBindings))
+	for action, binding := range im.actionBindings {
+		names[action] = binding.String()
+	}
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode bindings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write bindings: %w", err)
+	}
+	return nil
+}
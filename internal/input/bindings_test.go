@@ -0,0 +1,39 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+func TestBindingRoundTrip(t *testing.T) {
+	bindings := []Binding{
+		KeyBinding(glfw.KeySpace),
+		KeyBinding(glfw.KeyQ),
+		KeyBinding(glfw.KeyF7),
+		KeyBinding(glfw.KeyKP5), // No name, written as KEY_<code>
+		KeyBinding(glfw.KeyMenu),
+		MouseBinding(glfw.MouseButtonLeft),
+		MouseBinding(glfw.MouseButton5),
+		MouseBinding(glfw.MouseButton7), // No name, written as MOUSE_7
+	}
+	for _, b := range bindings {
+		name := b.String()
+		parsed, ok := ParseBinding(name)
+		if !ok {
+			t.Errorf("ParseBinding(%q) failed for %+v", name, b)
+			continue
+		}
+		if parsed != b {
+			t.Errorf("ParseBinding(%q) = %+v, want %+v", name, parsed, b)
+		}
+	}
+}
+
+func TestParseBindingRejects(t *testing.T) {
+	for _, name := range []string{"", "NOPE", "KEY_", "KEY_x", "KEY_-1", "KEY_99999", "MOUSE_0", "MOUSE_99999"} {
+		if b, ok := ParseBinding(name); ok {
+			t.Errorf("ParseBinding(%q) = %+v, want failure", name, b)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// gamepadDeadZone is how far a stick has to move off center before it
+// counts, so a stick resting slightly off center doesn't drift
+const gamepadDeadZone = 0.2
+
+// gamepadLookSpeed is how far the right stick turns the camera per second at
+// full tilt, in the same units as mouse movement
+const gamepadLookSpeed = 800
+
+// Triggers rest at -1 and read 1 fully pulled, past halfway counts as held
+const gamepadTriggerThreshold = 0
+
+// Gamepad buttons and triggers that hold actions down alongside their keys
+var gamepadButtons = map[string]glfw.GamepadButton{
+	"JUMP":   glfw.ButtonA,
+	"CROUCH": glfw.ButtonB,
+	"SPRINT": glfw.ButtonLeftThumb,
+	"PAUSE":  glfw.ButtonStart,
+}
+
+var gamepadTriggers = map[string]glfw.GamepadAxis{
+	"BREAK": glfw.AxisRightTrigger,
+	"PLACE": glfw.AxisLeftTrigger,
+}
+
+// findGamepad picks the first connected joystick with a gamepad mapping
+func (im *InputManager) findGamepad() {
+	im.hasGamepad = false
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if joy.IsGamepad() {
+			im.gamepad = joy
+			im.hasGamepad = true
+			fmt.Printf("Gamepad: %s\n", joy.GetGamepadName())
+			return
+		}
+	}
+}
+
+// joystickCallback handles controllers plugged in or removed mid-game
+func (im *InputManager) joystickCallback(joy glfw.Joystick, event glfw.PeripheralEvent) {
+	switch {
+	case event == glfw.Connected && !im.hasGamepad:
+		im.findGamepad()
+	case event == glfw.Disconnected && im.hasGamepad && joy == im.gamepad:
+		fmt.Println("Gamepad disconnected")
+		im.findGamepad()
+	}
+}
+
+// pollGamepad reads the gamepad for this frame. padState is nil without one.
+func (im *InputManager) pollGamepad() {
+	im.prevPadState = im.padState
+	im.padState = nil
+	if im.hasGamepad {
+		im.padState = im.gamepad.GetGamepadState()
+	}
+}
+
+// gamepadHolds reports whether the gamepad is holding an action down
+func (im *InputManager) gamepadHolds(action string) bool {
+	if im.padState == nil {
+		return false
+	}
+	if button, ok := gamepadButtons[action]; ok && im.padState.Buttons[button] == glfw.Press {
+		return true
+	}
+	if axis, ok := gamepadTriggers[action]; ok && im.padState.Axes[axis] > gamepadTriggerThreshold {
+		return true
+	}
+	return false
+}
+
+// gamepadJustPressed reports whether a button went down this frame
+func (im *InputManager) gamepadJustPressed(button glfw.GamepadButton) bool {
+	if im.padState == nil || im.padState.Buttons[button] != glfw.Press {
+		return false
+	}
+	return im.prevPadState == nil || im.prevPadState.Buttons[button] != glfw.Press
+}
+
+// updateGamepad turns the camera with the right stick and cycles the hotbar
+// with the bumpers. Movement and the held actions are read with the keyboard.
+func (im *InputManager) updateGamepad(deltaTime float32) {
+	if im.padState == nil || !im.cursorLocked || !im.focused {
+		return
+	}
+
+	look := im.stick(glfw.AxisRightX, glfw.AxisRightY)
+	if look.Len() > 0 {
+		// Stick up is negative, pushing up should look up
+		im.lookX += look.X() * gamepadLookSpeed * deltaTime
+		im.lookY -= look.Y() * gamepadLookSpeed * deltaTime
+	}
+
+	if im.gamepadJustPressed(glfw.ButtonRightBumper) {
+		im.cycleSlot(1)
+	}
+	if im.gamepadJustPressed(glfw.ButtonLeftBumper) {
+		im.cycleSlot(-1)
+	}
+}
+
+// stick reads a stick with the dead zone removed, rescaled so the edge of
+// the dead zone is 0 and full tilt is 1
+func (im *InputManager) stick(xAxis, yAxis glfw.GamepadAxis) mgl32.Vec2 {
+	if im.padState == nil {
+		return mgl32.Vec2{}
+	}
+	v := mgl32.Vec2{im.padState.Axes[xAxis], im.padState.Axes[yAxis]}
+	length := v.Len()
+	if length < gamepadDeadZone {
+		return mgl32.Vec2{}
+	}
+	return v.Mul(min(1, (length-gamepadDeadZone)/(1-gamepadDeadZone)) / length)
+}
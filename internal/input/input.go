@@ -2,6 +2,7 @@ package input
 
 import (
 	"fmt"
+	"strconv"
 	"voxel-game/internal/camera"
 	"voxel-game/internal/player"
 	"voxel-game/internal/world"
@@ -11,6 +12,14 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// editRepeatDelay is how often breaking or placing repeats, in seconds,
+// while the button is held
+const editRepeatDelay = 0.25
+
+// doubleTapWindow is how quickly, in seconds, jump has to be pressed twice
+// to toggle flying
+const doubleTapWindow = 0.3
+
 type InputManager struct {
 	window *glfw.Window
 	camera *camera.Camera
@@ -19,16 +28,58 @@ type InputManager struct {
 	firstMouse bool
 	lastX      float64
 	lastY      float64
-
-	selectedBlock world.BlockType
-	cursorLocked  bool
+	// Look movement gathered from the mouse and gamepad this frame
+	lookX, lookY float32
+
+	selectedSlot int
+	cursorLocked bool
+
+	// The pause menu is open: gameplay input is ignored and the cursor is
+	// free to click its buttons
+	menuOpen bool
+	// The console is open: keys type into it instead of playing
+	consoleOpen bool
+
+	// Time until a held break or place repeats, and whether the held place
+	// button is placing rather than using an interactive block
+	editCooldown float32
+	repeatPlace  bool
+
+	// Time left to make a second jump press count as a double tap
+	jumpTapTimer float32
+
+	// The connected gamepad, and its state this frame and last frame
+	gamepad      glfw.Joystick
+	hasGamepad   bool
+	padState     *glfw.GamepadState
+	prevPadState *glfw.GamepadState
+	focused      bool
+
+	// Freeze gameplay while the window is in the background
+	PauseOnFocusLoss bool
+
+	// Called when a place attempt fails, so the UI can tell the player why
+	OnPlaceDenied func(reason player.PlaceResult)
+	// Called when the world refuses to let the targeted block be broken
+	OnBreakDenied func()
+	// While the pause menu is open: called with the cursor position in
+	// window coordinates when it moves, and when the left mouse button is
+	// pressed or released
+	OnMenuCursor func(x, y float64)
+	OnMenuClick  func(x, y float64, pressed bool)
+	// While the console is open: called with each character typed, and
+	// with editing keys (Enter, Backspace, arrows, Page Up/Down) as they
+	// are pressed or repeat
+	OnConsoleChar func(ch rune)
+	OnConsoleKey  func(key glfw.Key)
 
 	//Debug State
 	debugMode bool
 	flySpeed  float32
 	wireframe *bool
+	cullFaces bool
 
-	actionBindings map[string]glfw.Key
+	actionBindings map[string]Binding
 	actionStates   map[string]*ActionState
 }
 
@@ -43,27 +94,41 @@ func NewInputManager(window *glfw.Window, cam *camera.Camera, p *player.Player,
 		camera:         cam,
 		player:         p,
 		firstMouse:     true,
-		selectedBlock:  world.BlockDirt,
+		selectedSlot:   0,
 		cursorLocked:   true,
+		focused:        true,
 		wireframe:      wireframe,
+		cullFaces:      true,
 		flySpeed:       20.0,
-		actionBindings: make(map[string]glfw.Key),
+		actionBindings: make(map[string]Binding),
 		actionStates:   make(map[string]*ActionState),
 	}
 
 	// Set up callbacks
 	window.SetCursorPosCallback(im.mouseCallback)
+	window.SetScrollCallback(im.scrollCallback)
 	window.SetMouseButtonCallback(im.mouseButtonCallback)
+	window.SetFocusCallback(im.focusCallback)
+	window.SetCharCallback(im.charCallback)
 	window.SetKeyCallback(im.keyCallback)
+	glfw.SetJoystickCallback(im.joystickCallback)
+	im.findGamepad()
 
 	// Register defaults
-	im.RegisterAction("TOGGLE_DEBUG", glfw.KeyG)
+	for name, binding := range defaultBindings {
+		im.RegisterBinding(name, binding)
+	}
 
 	return im
 }
 
 func (im *InputManager) RegisterAction(name string, key glfw.Key) {
-	im.actionBindings[name] = key
+	im.RegisterBinding(name, KeyBinding(key))
+}
+
+// RegisterBinding adds an action bound to a key or mouse button
+func (im *InputManager) RegisterBinding(name string, binding Binding) {
+	im.actionBindings[name] = binding
 	im.actionStates[name] = &ActionState{}
 }
 
@@ -72,26 +137,118 @@ func (i *InputManager) IsActionJustPressed(action string) bool {
 	return i.actionStates[action].JustPressed
 }
 
+// IsActionPressed reports whether the action's key or button is held down
+func (im *InputManager) IsActionPressed(action string) bool {
+	return im.actionStates[action].Pressed
+}
+
+// IsPaused reports whether gameplay should be frozen: the pause menu is
+// open, or the window lost focus. The console doesn't pause.
+func (im *InputManager) IsPaused() bool {
+	return im.menuOpen || (im.PauseOnFocusLoss && !im.focused)
+}
+
+// IsConsoleOpen reports whether the console is taking keyboard input
+func (im *InputManager) IsConsoleOpen() bool {
+	return im.consoleOpen
+}
+
+// SetConsoleOpen opens or closes the console. Like the menu it frees the
+// cursor while open.
+func (im *InputManager) SetConsoleOpen(open bool) {
+	im.consoleOpen = open
+	im.cursorLocked = !open
+	im.applyCursorMode()
+	if !open {
+		im.syncPressed()
+	}
+}
+
+// IsMenuOpen reports whether the pause menu is open
+func (im *InputManager) IsMenuOpen() bool {
+	return im.menuOpen
+}
+
+// SetMenuOpen opens or closes the pause menu. Opening frees the cursor,
+// closing locks it again for the camera.
+func (im *InputManager) SetMenuOpen(open bool) {
+	im.menuOpen = open
+	im.cursorLocked = !open
+	im.applyCursorMode()
+
+	if !open {
+		im.syncPressed()
+	}
+}
+
+// syncPressed marks everything held right now as already pressed. Whatever
+// closed a menu or the console, e.g. the click on Resume, is still held and
+// mustn't count as a fresh press once gameplay resumes.
+func (im *InputManager) syncPressed() {
+	for name, binding := range im.actionBindings {
+		im.actionStates[name].Pressed = im.bindingDown(name, binding)
+	}
+}
+
 func (im *InputManager) IsDebugMode() bool {
 	return im.debugMode
 }
 
-func (im *InputManager) GetSelectedBlock() world.BlockType {
-	return im.selectedBlock
+// CullFaces reports whether back-face culling should be on for the world pass
+func (im *InputManager) CullFaces() bool {
+	return im.cullFaces
 }
 
-func (im *InputManager) Update(deltaTime float32) {
-	if im.window.GetKey(glfw.KeyEscape) == glfw.Press {
-		im.window.SetShouldClose(true)
+// GetSelectedSlot returns the selected hotbar slot index
+func (im *InputManager) GetSelectedSlot() int {
+	return im.selectedSlot
+}
+
+// GetSelectedBlock returns the block in the selected hotbar slot, or air if it's empty
+func (im *InputManager) GetSelectedBlock() world.BlockType {
+	slot := im.player.Inventory.Slots[im.selectedSlot]
+	if slot.Count == 0 {
+		return world.BlockAir
 	}
+	return slot.Type
+}
 
-	for name, key := range im.actionBindings {
-		isDown := im.window.GetKey(key) == glfw.Press
+func (im *InputManager) Update(deltaTime float32) {
+	im.pollGamepad()
+	for name, binding := range im.actionBindings {
+		isDown := im.bindingDown(name, binding)
 		state := im.actionStates[name]
 
-		state.JustPressed = isDown && !state.Pressed
+		// Keys pressed behind the pause menu or typed into the console don't
+		// trigger their actions, only PAUSE and CONSOLE get through to close them
+		blocked := (im.menuOpen || im.consoleOpen) && name != "PAUSE" && name != "CONSOLE"
+		state.JustPressed = isDown && !state.Pressed && !blocked
 		state.Pressed = isDown
 	}
+
+	switch {
+	case im.IsActionJustPressed("PAUSE") && im.consoleOpen:
+		// Escape backs out of the console rather than opening the menu
+		im.SetConsoleOpen(false)
+	case im.IsActionJustPressed("PAUSE"):
+		im.SetMenuOpen(!im.menuOpen)
+	case im.IsActionJustPressed("CONSOLE") && !im.menuOpen:
+		im.SetConsoleOpen(!im.consoleOpen)
+	}
+	if im.menuOpen || im.consoleOpen {
+		im.lookX, im.lookY = 0, 0
+		return
+	}
+
+	im.handleActions()
+	im.updateBlockEdits(deltaTime)
+	im.updateGamepad(deltaTime)
+
+	// Turn once per frame with everything gathered, so smoothing sees frames
+	// rather than individual cursor events
+	im.camera.ProcessMouseMovement(im.lookX, im.lookY)
+	im.lookX, im.lookY = 0, 0
+
 	// STATE MACHINE: Switch controls based on mode
 	if im.debugMode {
 		im.updateDebugCamera(deltaTime)
@@ -100,77 +257,137 @@ func (im *InputManager) Update(deltaTime float32) {
 	}
 }
 
+// bindingDown reports whether an action's key or button, or its gamepad
+// button, is held right now
+func (im *InputManager) bindingDown(name string, binding Binding) bool {
+	if binding.Mouse && im.window.GetMouseButton(binding.Button) == glfw.Press {
+		return true
+	}
+	if !binding.Mouse && im.window.GetKey(binding.Key) == glfw.Press {
+		return true
+	}
+	return im.gamepadHolds(name)
+}
+
 func (im *InputManager) updatePlayer(deltaTime float32) {
 	var moveDir mgl32.Vec3
 
 	// Standard WASD
-	if im.window.GetKey(glfw.KeyW) == glfw.Press {
+	if im.IsActionPressed("FORWARD") {
 		forward := im.camera.Front
 		forward[1] = 0 // Keep player stuck to ground plane
 		forward = forward.Normalize()
 		moveDir = moveDir.Add(forward)
 	}
-	if im.window.GetKey(glfw.KeyS) == glfw.Press {
+	if im.IsActionPressed("BACK") {
 		forward := im.camera.Front
 		forward[1] = 0
 		forward = forward.Normalize()
 		moveDir = moveDir.Sub(forward)
 	}
-	if im.window.GetKey(glfw.KeyA) == glfw.Press {
+	if im.IsActionPressed("LEFT") {
 		moveDir = moveDir.Sub(im.camera.Right)
 	}
-	if im.window.GetKey(glfw.KeyD) == glfw.Press {
+	if im.IsActionPressed("RIGHT") {
 		moveDir = moveDir.Add(im.camera.Right)
 	}
 
-	// Apply movement
+	// The left stick adds to the keys, stick up is negative
+	if move := im.stick(glfw.AxisLeftX, glfw.AxisLeftY); move.Len() > 0 {
+		forward := im.camera.Front
+		forward[1] = 0
+		forward = forward.Normalize()
+		moveDir = moveDir.Add(forward.Mul(-move.Y())).Add(im.camera.Right.Mul(move.X()))
+	}
+
+	// Double-tapping jump toggles creative flight
+	im.jumpTapTimer = max(0, im.jumpTapTimer-deltaTime)
+	if im.IsActionJustPressed("JUMP") {
+		if im.jumpTapTimer > 0 {
+			im.toggleFlying()
+			im.jumpTapTimer = 0
+		} else {
+			im.jumpTapTimer = doubleTapWindow
+		}
+	}
+	if im.IsActionJustPressed("TOGGLE_FLY") {
+		im.toggleFlying()
+	}
+
+	im.player.SetSprinting(im.IsActionPressed("SPRINT"))
+	// While flying the crouch key flies down instead
+	im.player.SetCrouching(im.IsActionPressed("CROUCH") && !im.player.IsFlying())
+
+	// Apply movement. A partly tilted stick stays below full strength.
 	if moveDir.Len() > 0 {
-		moveDir = moveDir.Normalize()
+		if moveDir.Len() > 1 {
+			moveDir = moveDir.Normalize()
+		}
 		im.player.Move(moveDir, deltaTime)
 	}
 
 	// Player Actions
-	if im.window.GetKey(glfw.KeySpace) == glfw.Press {
+	if im.player.IsFlying() {
+		switch {
+		case im.IsActionPressed("JUMP"):
+			im.player.Fly(1)
+		case im.IsActionPressed("CROUCH"):
+			im.player.Fly(-1)
+		}
+	} else if im.IsActionPressed("JUMP") {
 		im.player.Jump()
 	}
 }
 
+// toggleFlying starts or stops creative flight, which only creative mode allows
+func (im *InputManager) toggleFlying() {
+	if im.player.Mode != player.Creative {
+		return
+	}
+	im.player.SetFlying(!im.player.IsFlying())
+	fmt.Printf("Flying: %v\n", im.player.IsFlying())
+}
+
 func (im *InputManager) updateDebugCamera(deltaTime float32) {
 	// Calculate Speed
 	currentSpeed := im.flySpeed
-	if im.window.GetKey(glfw.KeyLeftShift) == glfw.Press {
+	if im.IsActionPressed("SPRINT") {
 		currentSpeed *= 3.0 // Sprint (Fast Fly)
 	}
-	if im.window.GetKey(glfw.KeyLeftControl) == glfw.Press {
+	if im.IsActionPressed("CROUCH") {
 		currentSpeed *= 0.1 // Precision Mode (Slow Fly)
 	}
 
 	// Free Fly Movement (Moves Camera.Position directly)
 	// W/S = Forward/Backward (in looking direction)
-	if im.window.GetKey(glfw.KeyW) == glfw.Press {
+	if im.IsActionPressed("FORWARD") {
 		im.camera.Position = im.camera.Position.Add(im.camera.Front.Mul(currentSpeed * deltaTime))
 	}
-	if im.window.GetKey(glfw.KeyS) == glfw.Press {
+	if im.IsActionPressed("BACK") {
 		im.camera.Position = im.camera.Position.Sub(im.camera.Front.Mul(currentSpeed * deltaTime))
 	}
 	// A/D = Strafe Left/Right
-	if im.window.GetKey(glfw.KeyA) == glfw.Press {
+	if im.IsActionPressed("LEFT") {
 		im.camera.Position = im.camera.Position.Sub(im.camera.Right.Mul(currentSpeed * deltaTime))
 	}
-	if im.window.GetKey(glfw.KeyD) == glfw.Press {
+	if im.IsActionPressed("RIGHT") {
 		im.camera.Position = im.camera.Position.Add(im.camera.Right.Mul(currentSpeed * deltaTime))
 	}
 	// Space/Alt = Up/Down (Absolute World Up)
-	if im.window.GetKey(glfw.KeySpace) == glfw.Press {
+	if im.IsActionPressed("JUMP") {
 		im.camera.Position = im.camera.Position.Add(im.camera.WorldUp.Mul(currentSpeed * deltaTime))
 	}
-	if im.window.GetKey(glfw.KeyLeftAlt) == glfw.Press {
+	if im.IsActionPressed("FLY_DOWN") {
 		im.camera.Position = im.camera.Position.Sub(im.camera.WorldUp.Mul(currentSpeed * deltaTime))
 	}
 }
 
 func (im *InputManager) mouseCallback(w *glfw.Window, xpos, ypos float64) {
-	if !im.cursorLocked {
+	if im.menuOpen && im.OnMenuCursor != nil {
+		im.OnMenuCursor(xpos, ypos)
+		return
+	}
+	if !im.cursorLocked || !im.focused {
 		return
 	}
 	if im.firstMouse {
@@ -185,76 +402,204 @@ func (im *InputManager) mouseCallback(w *glfw.Window, xpos, ypos float64) {
 	im.lastX = xpos
 	im.lastY = ypos
 
-	im.camera.ProcessMouseMovement(float32(xoffset), float32(yoffset))
+	im.lookX += float32(xoffset)
+	im.lookY += float32(yoffset)
 }
 
+// scrollCallback cycles the hotbar selection, wrapping at either end.
+// Scrolling up moves right. Ignored while the cursor is free for menus.
+func (im *InputManager) scrollCallback(w *glfw.Window, xoff, yoff float64) {
+	if !im.cursorLocked || !im.focused || yoff == 0 {
+		return
+	}
+	if yoff > 0 {
+		im.cycleSlot(1)
+	} else {
+		im.cycleSlot(-1)
+	}
+}
+
+// mouseButtonCallback forwards left button presses and releases to the
+// pause menu while it is open. Gameplay clicks are read through the BREAK
+// and PLACE bindings.
 func (im *InputManager) mouseButtonCallback(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
-	if action == glfw.Press {
-		if button == glfw.MouseButtonLeft {
-			// Break block
-			im.player.BreakBlock()
-		}
+	if !im.menuOpen || button != glfw.MouseButtonLeft || action == glfw.Repeat || im.OnMenuClick == nil {
+		return
 	}
+	x, y := w.GetCursorPos()
+	im.OnMenuClick(x, y, action == glfw.Press)
 }
 
-func (im *InputManager) keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-	if action == glfw.Press {
-		// Number keys to select block type
-		switch key {
-		case glfw.Key1:
-			im.selectedBlock = world.BlockDirt
-		case glfw.Key2:
-			im.selectedBlock = world.BlockGrass
-		case glfw.Key3:
-			im.selectedBlock = world.BlockStone
-		case glfw.Key4:
-			im.selectedBlock = world.BlockSnow
-		case glfw.Key5:
-			im.selectedBlock = world.BlockSand
-		case glfw.Key6:
-			im.selectedBlock = world.BlockWood
-		case glfw.KeyTab:
-			im.cursorLocked = !im.cursorLocked
-			if im.cursorLocked {
-				w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
-			} else {
-				w.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
-			}
+// cycleSlot moves the hotbar selection by step, wrapping at either end
+func (im *InputManager) cycleSlot(step int) {
+	im.selectedSlot = (im.selectedSlot + step + player.HotbarSize) % player.HotbarSize
+}
 
-		case glfw.KeyB:
-			// Place block
-			im.player.PlaceBlock(im.selectedBlock)
-
-		case glfw.KeyG:
-			im.debugMode = !im.debugMode
-			fmt.Printf("Debug Mode: %v\n", im.debugMode)
-			// Unfreeze frustum when exiting debug mode so we don't get stuck with a weird view
-			if !im.debugMode {
-				im.player.TeleportToCamera()
-				im.camera.FrustumFrozen = false
-				// Force wireframe off when leaving debug mode
-				if *im.wireframe {
-					*im.wireframe = false
-					gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
-				}
-			}
-		case glfw.KeyF:
-			if im.debugMode {
-				*im.wireframe = !*im.wireframe
-				if *im.wireframe {
-					gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
-				} else {
-					gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
-				}
-				fmt.Printf("Wireframe: %v\n", *im.wireframe)
+// handleActions runs the built-in actions pressed this frame
+func (im *InputManager) handleActions() {
+	for i := 0; i < player.HotbarSize; i++ {
+		if im.IsActionJustPressed("HOTBAR_" + strconv.Itoa(i+1)) {
+			im.selectedSlot = i
+		}
+	}
+
+	if im.IsActionJustPressed("TOGGLE_CURSOR") {
+		im.cursorLocked = !im.cursorLocked
+		im.applyCursorMode()
+	}
+
+	if im.IsActionJustPressed("TOGGLE_DEBUG") {
+		im.debugMode = !im.debugMode
+		fmt.Printf("Debug Mode: %v\n", im.debugMode)
+		// The free camera may look straight up/down, gameplay keeps the margin
+		if im.debugMode {
+			im.camera.SetPitchLimits(-camera.FullPitchLimit, camera.FullPitchLimit)
+		} else {
+			im.camera.SetPitchLimits(-camera.DefaultPitchLimit, camera.DefaultPitchLimit)
+		}
+		// Unfreeze frustum when exiting debug mode so we don't get stuck with a weird view
+		if !im.debugMode {
+			im.player.TeleportToCamera()
+			im.camera.FrustumFrozen = false
+			// Force wireframe off when leaving debug mode
+			if *im.wireframe {
+				*im.wireframe = false
+				gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
 			}
+			im.cullFaces = true
+		}
+	}
+
+	if im.IsActionJustPressed("WIREFRAME") && im.debugMode {
+		*im.wireframe = !*im.wireframe
+		if *im.wireframe {
+			gl.PolygonMode(gl.FRONT_AND_BACK, gl.LINE)
+		} else {
+			gl.PolygonMode(gl.FRONT_AND_BACK, gl.FILL)
+		}
+		fmt.Printf("Wireframe: %v\n", *im.wireframe)
+	}
 
-		case glfw.KeyP:
-			// Toggle Frustum Freeze (Only works in Debug Mode)
-			if im.debugMode {
-				im.camera.FrustumFrozen = !im.camera.FrustumFrozen
-				fmt.Printf("Frustum Frozen: %v\n", im.camera.FrustumFrozen)
+	// Toggle back-face culling to spot faces with wrong winding
+	if im.IsActionJustPressed("TOGGLE_CULL") && im.debugMode {
+		im.cullFaces = !im.cullFaces
+		fmt.Printf("Face Culling: %v\n", im.cullFaces)
+	}
+
+	if im.IsActionJustPressed("TOGGLE_MODE") {
+		if im.player.Mode == player.Creative {
+			im.player.Mode = player.Survival
+		} else {
+			im.player.Mode = player.Creative
+		}
+		fmt.Printf("Survival Mode: %v\n", im.player.Mode == player.Survival)
+	}
+
+	// Frustum freeze only works in debug mode
+	if im.IsActionJustPressed("FREEZE_FRUSTUM") && im.debugMode {
+		im.camera.FrustumFrozen = !im.camera.FrustumFrozen
+		fmt.Printf("Frustum Frozen: %v\n", im.camera.FrustumFrozen)
+	}
+}
+
+// updateBlockEdits breaks or places on a press, then repeats every
+// editRepeatDelay while the button is held. Only the press itself reports a
+// refused edit, so holding against a protected block doesn't spam the UI.
+func (im *InputManager) updateBlockEdits(deltaTime float32) {
+	breakPressed := im.IsActionJustPressed("BREAK")
+	placePressed := im.IsActionJustPressed("PLACE")
+	placeAltPressed := im.IsActionJustPressed("PLACE_ALT")
+
+	if breakPressed || placePressed || placeAltPressed {
+		im.editCooldown = editRepeatDelay
+		if breakPressed {
+			im.breakBlock(true)
+		}
+		if placePressed {
+			// Interactive blocks take the click, otherwise place
+			im.repeatPlace = !im.player.UseBlock()
+			if im.repeatPlace {
+				im.placeBlock(true)
 			}
 		}
+		if placeAltPressed {
+			im.placeBlock(true)
+		}
+		return
+	}
+
+	breakHeld := im.IsActionPressed("BREAK")
+	placeHeld := (im.IsActionPressed("PLACE") && im.repeatPlace) || im.IsActionPressed("PLACE_ALT")
+	if !breakHeld && !placeHeld {
+		return
+	}
+	im.editCooldown -= deltaTime
+	if im.editCooldown > 0 {
+		return
+	}
+	im.editCooldown += editRepeatDelay
+	if breakHeld {
+		im.breakBlock(false)
+	}
+	if placeHeld {
+		im.placeBlock(false)
+	}
+}
+
+// breakBlock breaks the targeted block. A miss is silent, a refused edit is
+// reported to OnBreakDenied if report is set.
+func (im *InputManager) breakBlock(report bool) {
+	if !im.player.BreakBlock() && report && im.player.TargetBlock().Hit && im.OnBreakDenied != nil {
+		im.OnBreakDenied()
+	}
+}
+
+// placeBlock places the selected block, reporting a denial to
+// OnPlaceDenied if report is set
+func (im *InputManager) placeBlock(report bool) {
+	result := im.player.PlaceBlock(im.selectedSlot)
+	if result != player.Placed && report && im.OnPlaceDenied != nil {
+		im.OnPlaceDenied(result)
+	}
+}
+
+func (im *InputManager) focusCallback(w *glfw.Window, focused bool) {
+	im.focused = focused
+	im.applyCursorMode()
+}
+
+// charCallback types text into the open console. The console key's own
+// character is left out, it is still held when the console opens or closes.
+func (im *InputManager) charCallback(w *glfw.Window, ch rune) {
+	if !im.consoleOpen || im.OnConsoleChar == nil {
+		return
+	}
+	if im.bindingDown("CONSOLE", im.actionBindings["CONSOLE"]) {
+		return
+	}
+	im.OnConsoleChar(ch)
+}
+
+// keyCallback passes the console its editing keys, with key repeat so
+// holding Backspace keeps deleting. Gameplay keys are polled in Update.
+func (im *InputManager) keyCallback(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	if !im.consoleOpen || action == glfw.Release || im.OnConsoleKey == nil {
+		return
+	}
+	switch key {
+	case glfw.KeyEnter, glfw.KeyKPEnter, glfw.KeyBackspace, glfw.KeyUp, glfw.KeyDown, glfw.KeyPageUp, glfw.KeyPageDown:
+		im.OnConsoleKey(key)
+	}
+}
+
+// applyCursorMode captures the cursor only when locked and the window has focus.
+// Re-capturing resets the mouse delta so the camera doesn't jump to wherever
+// the cursor wandered while it was free.
+func (im *InputManager) applyCursorMode() {
+	if im.cursorLocked && im.focused {
+		im.window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+		im.firstMouse = true
+	} else {
+		im.window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
 	}
 }
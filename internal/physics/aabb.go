@@ -0,0 +1,85 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// AABB is an axis-aligned bounding box in world space
+type AABB struct {
+	Min mgl32.Vec3
+	Max mgl32.Vec3
+}
+
+func NewAABB(min, max mgl32.Vec3) AABB {
+	return AABB{Min: min, Max: max}
+}
+
+// BodyAABB builds the box of an upright body standing at feet, centered on X/Z
+func BodyAABB(feet mgl32.Vec3, width, height float32) AABB {
+	half := width / 2
+	return AABB{
+		Min: mgl32.Vec3{feet[0] - half, feet[1], feet[2] - half},
+		Max: mgl32.Vec3{feet[0] + half, feet[1] + height, feet[2] + half},
+	}
+}
+
+// BlockAABB is the unit cube occupied by the block at x, y, z
+func BlockAABB(x, y, z int) AABB {
+	min := mgl32.Vec3{float32(x), float32(y), float32(z)}
+	return AABB{Min: min, Max: min.Add(mgl32.Vec3{1, 1, 1})}
+}
+
+// Intersects reports whether the boxes overlap. Touching faces don't count.
+func (a AABB) Intersects(b AABB) bool {
+	return a.Min[0] < b.Max[0] && a.Max[0] > b.Min[0] &&
+		a.Min[1] < b.Max[1] && a.Max[1] > b.Min[1] &&
+		a.Min[2] < b.Max[2] && a.Max[2] > b.Min[2]
+}
+
+// Expand grows the box by amount on every side (negative values shrink it)
+func (a AABB) Expand(amount mgl32.Vec3) AABB {
+	return AABB{Min: a.Min.Sub(amount), Max: a.Max.Add(amount)}
+}
+
+func (a AABB) Translate(offset mgl32.Vec3) AABB {
+	return AABB{Min: a.Min.Add(offset), Max: a.Max.Add(offset)}
+}
+
+// BlockRange returns the inclusive range of block coordinates the box touches
+func (a AABB) BlockRange() (minX, minY, minZ, maxX, maxY, maxZ int) {
+	floor := func(v float32) int {
+		return int(math.Floor(float64(v)))
+	}
+	return floor(a.Min[0]), floor(a.Min[1]), floor(a.Min[2]),
+		floor(a.Max[0]), floor(a.Max[1]), floor(a.Max[2])
+}
+
+// RayDistance returns how far along dir a ray from origin first enters the
+// box (slab test). dir need not be normalized; the distance is in multiples
+// of dir. ok is false if the ray misses or the box lies behind the origin.
+func (a AABB) RayDistance(origin, dir mgl32.Vec3) (dist float32, ok bool) {
+	tMin := float32(0)
+	tMax := float32(math.MaxFloat32)
+
+	for i := 0; i < 3; i++ {
+		if dir[i] == 0 {
+			if origin[i] < a.Min[i] || origin[i] > a.Max[i] {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (a.Min[i] - origin[i]) / dir[i]
+		t2 := (a.Max[i] - origin[i]) / dir[i]
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = max(tMin, t1)
+		tMax = min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+	return tMin, true
+}
@@ -0,0 +1,126 @@
+package physics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestIntersects(t *testing.T) {
+	block := BlockAABB(0, 0, 0)
+	tests := []struct {
+		name  string
+		other AABB
+		want  bool
+	}{
+		{"same box", BlockAABB(0, 0, 0), true},
+		{"overlapping corner", NewAABB(mgl32.Vec3{0.5, 0.5, 0.5}, mgl32.Vec3{1.5, 1.5, 1.5}), true},
+		{"inside", NewAABB(mgl32.Vec3{0.25, 0.25, 0.25}, mgl32.Vec3{0.75, 0.75, 0.75}), true},
+		{"apart", BlockAABB(3, 0, 0), false},
+		{"touching +X", BlockAABB(1, 0, 0), false},
+		{"touching -X", BlockAABB(-1, 0, 0), false},
+		{"touching +Y", BlockAABB(0, 1, 0), false},
+		{"touching -Y", BlockAABB(0, -1, 0), false},
+		{"touching +Z", BlockAABB(0, 0, 1), false},
+		{"touching -Z", BlockAABB(0, 0, -1), false},
+		{"touching edge", BlockAABB(1, 1, 0), false},
+		{"overlapping X only", NewAABB(mgl32.Vec3{0.5, 2, 0}, mgl32.Vec3{1.5, 3, 1}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := block.Intersects(tt.other); got != tt.want {
+				t.Errorf("Intersects = %v, want %v", got, tt.want)
+			}
+			if got := tt.other.Intersects(block); got != tt.want {
+				t.Errorf("reversed Intersects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyAABB(t *testing.T) {
+	box := BodyAABB(mgl32.Vec3{2.5, 10, -3.5}, 0.6, 1.8)
+	wantMin := mgl32.Vec3{2.2, 10, -3.8}
+	wantMax := mgl32.Vec3{2.8, 11.8, -3.2}
+	if !box.Min.ApproxEqual(wantMin) || !box.Max.ApproxEqual(wantMax) {
+		t.Errorf("BodyAABB = %v to %v, want %v to %v", box.Min, box.Max, wantMin, wantMax)
+	}
+
+	// Standing on a block touches it without overlapping
+	if box.Intersects(BlockAABB(2, 9, -4)) {
+		t.Error("body intersects the block under its feet")
+	}
+	minX, minY, minZ, maxX, maxY, maxZ := box.BlockRange()
+	if [6]int{minX, minY, minZ, maxX, maxY, maxZ} != [6]int{2, 10, -4, 2, 11, -4} {
+		t.Errorf("BlockRange = %d %d %d to %d %d %d, want 2 10 -4 to 2 11 -4",
+			minX, minY, minZ, maxX, maxY, maxZ)
+	}
+}
+
+func TestExpandTranslate(t *testing.T) {
+	box := NewAABB(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{2, 4, 6})
+	tests := []struct {
+		name     string
+		got      AABB
+		min, max mgl32.Vec3
+	}{
+		{"expand", box.Expand(mgl32.Vec3{0.5, 1, 0}), mgl32.Vec3{0.5, 1, 3}, mgl32.Vec3{2.5, 5, 6}},
+		{"shrink", box.Expand(mgl32.Vec3{-0.25, -0.5, -1}), mgl32.Vec3{1.25, 2.5, 4}, mgl32.Vec3{1.75, 3.5, 5}},
+		{"expand nothing", box.Expand(mgl32.Vec3{}), box.Min, box.Max},
+		{"translate", box.Translate(mgl32.Vec3{10, -5, 0.5}), mgl32.Vec3{11, -3, 3.5}, mgl32.Vec3{12, -1, 6.5}},
+		{"translate back", box.Translate(mgl32.Vec3{-1, -2, -3}), mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		if !tt.got.Min.ApproxEqual(tt.min) || !tt.got.Max.ApproxEqual(tt.max) {
+			t.Errorf("%s: %v to %v, want %v to %v", tt.name, tt.got.Min, tt.got.Max, tt.min, tt.max)
+		}
+	}
+}
+
+func TestBlockRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		box      AABB
+		min, max [3]int
+	}{
+		{"one block", BlockAABB(2, 3, 4), [3]int{2, 3, 4}, [3]int{3, 4, 5}},
+		{"inside one block", NewAABB(mgl32.Vec3{0.2, 0.2, 0.2}, mgl32.Vec3{0.8, 0.8, 0.8}), [3]int{0, 0, 0}, [3]int{0, 0, 0}},
+		{"fractional", NewAABB(mgl32.Vec3{1.5, 10, 2.25}, mgl32.Vec3{2.1, 11.8, 2.75}), [3]int{1, 10, 2}, [3]int{2, 11, 2}},
+		{"negative", NewAABB(mgl32.Vec3{-0.3, -2.5, -16}, mgl32.Vec3{0.3, -0.5, -15.5}), [3]int{-1, -3, -16}, [3]int{0, -1, -16}},
+		{"negative whole", BlockAABB(-1, -1, -1), [3]int{-1, -1, -1}, [3]int{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		minX, minY, minZ, maxX, maxY, maxZ := tt.box.BlockRange()
+		if [3]int{minX, minY, minZ} != tt.min || [3]int{maxX, maxY, maxZ} != tt.max {
+			t.Errorf("%s: BlockRange = %d %d %d to %d %d %d, want %v to %v",
+				tt.name, minX, minY, minZ, maxX, maxY, maxZ, tt.min, tt.max)
+		}
+	}
+}
+
+func TestRayDistance(t *testing.T) {
+	block := BlockAABB(0, 0, 0)
+	tests := []struct {
+		name        string
+		origin, dir mgl32.Vec3
+		dist        float32
+		ok          bool
+	}{
+		{"+X", mgl32.Vec3{-2, 0.5, 0.5}, mgl32.Vec3{1, 0, 0}, 2, true},
+		{"-Y", mgl32.Vec3{0.5, 5, 0.5}, mgl32.Vec3{0, -1, 0}, 4, true},
+		{"unnormalized", mgl32.Vec3{0.5, 0.5, -3}, mgl32.Vec3{0, 0, 2}, 1.5, true},
+		{"diagonal", mgl32.Vec3{-1, -1, 0.5}, mgl32.Vec3{1, 1, 0}, 1, true},
+		{"from inside", mgl32.Vec3{0.5, 0.5, 0.5}, mgl32.Vec3{1, 0, 0}, 0, true},
+		{"behind", mgl32.Vec3{3, 0.5, 0.5}, mgl32.Vec3{1, 0, 0}, 0, false},
+		{"passes beside", mgl32.Vec3{-2, 1.5, 0.5}, mgl32.Vec3{1, 0, 0}, 0, false},
+		{"parallel outside", mgl32.Vec3{-2, 0.5, 2}, mgl32.Vec3{1, 0, 0}, 0, false},
+		{"misses diagonally", mgl32.Vec3{-1, 0.5, -2}, mgl32.Vec3{1, 0, 0.2}, 0, false},
+	}
+	for _, tt := range tests {
+		dist, ok := block.RayDistance(tt.origin, tt.dir)
+		if ok != tt.ok || (ok && math.Abs(float64(dist-tt.dist)) > 1e-5) {
+			t.Errorf("%s: RayDistance = %v, %v, want %v, %v", tt.name, dist, ok, tt.dist, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package player
+
+import "github.com/go-gl/mathgl/mgl32"
+
+const (
+	MaxHealth = 20.0
+
+	// Falls up to this many blocks are free, each block past it costs one
+	// health
+	safeFallDistance = 3.0
+)
+
+// trackFall remembers the highest point of the current fall and, on
+// landing, takes damage for the distance dropped. Water and flight break a
+// fall. Only survival players are hurt.
+func (p *Player) trackFall() {
+	if p.inWater || p.flying {
+		p.falling = false
+		return
+	}
+	y := p.PhysicsPos.Y()
+	if !p.grounded {
+		if !p.falling || y > p.fallStartY {
+			p.fallStartY = y
+		}
+		p.falling = true
+		return
+	}
+	if !p.falling {
+		return
+	}
+
+	p.falling = false
+	if distance := p.fallStartY - y; distance > safeFallDistance && p.Mode == Survival {
+		p.Damage(distance - safeFallDistance)
+	}
+}
+
+// Damage takes health away. At zero the player dies and respawns.
+func (p *Player) Damage(amount float32) {
+	p.Health = max(0, p.Health-amount)
+	if p.Health > 0 {
+		return
+	}
+	p.Respawn()
+	if p.OnDeath != nil {
+		p.OnDeath()
+	}
+}
+
+// Respawn puts the player back on the surface at the spawn point with full
+// health
+func (p *Player) Respawn() {
+	p.PhysicsPos = p.spawnPos
+	p.spawnOnSurface()
+	p.velocity = mgl32.Vec3{0, 0, 0}
+	p.grounded = false
+	p.falling = false
+	p.flying = false
+	p.Health = MaxHealth
+}
@@ -0,0 +1,122 @@
+package player
+
+import "voxel-game/internal/world"
+
+const (
+	MaxStackSize = 64
+	// The first HotbarSize inventory slots are shown in the hotbar
+	HotbarSize = 7
+)
+
+type ItemStack struct {
+	Type  world.BlockType
+	Count int
+}
+
+type Inventory struct {
+	Slots []ItemStack
+}
+
+func NewInventory(size int) *Inventory {
+	return &Inventory{
+		Slots: make([]ItemStack, size),
+	}
+}
+
+// Add puts items into existing stacks first, then empty slots.
+// Returns how many didn't fit.
+func (inv *Inventory) Add(blockType world.BlockType, count int) int {
+	for i := range inv.Slots {
+		if count == 0 {
+			return 0
+		}
+		slot := &inv.Slots[i]
+		if slot.Count > 0 && slot.Type == blockType {
+			count = slot.fill(count)
+		}
+	}
+
+	for i := range inv.Slots {
+		if count == 0 {
+			return 0
+		}
+		slot := &inv.Slots[i]
+		if slot.Count == 0 {
+			slot.Type = blockType
+			count = slot.fill(count)
+		}
+	}
+
+	return count
+}
+
+// Remove takes up to count items of a type out of the inventory, hotbar
+// slots first. Returns how many were removed.
+func (inv *Inventory) Remove(blockType world.BlockType, count int) int {
+	removed := 0
+	for i := range inv.Slots {
+		slot := &inv.Slots[i]
+		if removed == count {
+			break
+		}
+		if slot.Count == 0 || slot.Type != blockType {
+			continue
+		}
+		take := min(slot.Count, count-removed)
+		slot.Count -= take
+		removed += take
+		if slot.Count == 0 {
+			*slot = ItemStack{}
+		}
+	}
+	return removed
+}
+
+// RemoveAt takes up to count items out of one slot. Returns how many were
+// removed.
+func (inv *Inventory) RemoveAt(slot, count int) int {
+	s := &inv.Slots[slot]
+	take := min(s.Count, count)
+	s.Count -= take
+	if s.Count == 0 {
+		*s = ItemStack{}
+	}
+	return take
+}
+
+// Hotbar returns the slots shown in the hotbar
+func (inv *Inventory) Hotbar() []ItemStack {
+	return inv.Slots[:HotbarSize]
+}
+
+// SetHotbar replaces the hotbar slots with stacks, clearing any slot not covered
+func (inv *Inventory) SetHotbar(stacks []ItemStack) {
+	for i := 0; i < HotbarSize; i++ {
+		if i < len(stacks) {
+			inv.Slots[i] = stacks[i]
+		} else {
+			inv.Slots[i] = ItemStack{}
+		}
+	}
+}
+
+// Count returns the total number of items of a type across all slots
+func (inv *Inventory) Count(blockType world.BlockType) int {
+	total := 0
+	for _, slot := range inv.Slots {
+		if slot.Type == blockType {
+			total += slot.Count
+		}
+	}
+	return total
+}
+
+func (s *ItemStack) fill(count int) int {
+	space := MaxStackSize - s.Count
+	if count <= space {
+		s.Count += count
+		return 0
+	}
+	s.Count = MaxStackSize
+	return count - space
+}
@@ -4,15 +4,38 @@ import (
 	"math"
 
 	"voxel-game/internal/camera"
+	"voxel-game/internal/physics"
 	"voxel-game/internal/world"
 
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+type GameMode int
+
+const (
+	// Creative has unlimited blocks and ignores drops
+	Creative GameMode = iota
+	// Survival collects block drops into the inventory and uses them up when
+	// placing
+	Survival
+)
+
 type TargetBlock struct {
 	Hit  bool
 	Pos  mgl32.Vec3
 	Face int
+	Type world.BlockType
+}
+
+// faceNames are the sides of a block by face index, north being -Z
+var faceNames = [6]string{"South", "North", "East", "West", "Top", "Bottom"}
+
+// FaceName is the side of a block a face index is, e.g. "Top"
+func FaceName(face int) string {
+	if face < 0 || face >= len(faceNames) {
+		return "?"
+	}
+	return faceNames[face]
 }
 
 type Player struct {
@@ -21,37 +44,123 @@ type Player struct {
 
 	PhysicsPos mgl32.Vec3
 
-	walkSpeed float32
-	jumpForce float32
-	velocity  mgl32.Vec3
+	walkSpeed   float32
+	sprintSpeed float32
+	jumpForce   float32
+	velocity    mgl32.Vec3
+
+	sprinting bool
+	crouching bool
+	// Creative flight: no gravity, but blocks still collide
+	flying bool
+	// Camera height still to catch up after stepping up a ledge, <= 0
+	stepOffset float32
+	// While crouching with nothing targeted, place against the side of the
+	// block underfoot (bridging)
+	BridgePlacement bool
+	// Only sprint when moving within sprintConeDeg of the camera facing
+	SprintForwardOnly bool
 
 	grounded bool
-	width    float32
-	height   float32
+	// Water state, refreshed every Update. headInWater means the eyes are
+	// under the surface.
+	inWater     bool
+	headInWater bool
+	// Set when horizontal movement was blocked by a block this frame, lets
+	// a swimmer climb out onto a bank
+	againstWall bool
+	width       float32
+	height      float32
 
 	target TargetBlock
 
 	walkingTime float32
+	swingTime   float32 // Counts down while the arm swings after an edit
+
+	// Held in place until the terrain underfoot has loaded
+	waitingForTerrain bool
+
+	Mode      GameMode
+	Inventory *Inventory
+
+	// Health from 0 to MaxHealth, lost to falls in survival
+	Health float32
+	// Highest point of the fall in progress
+	falling    bool
+	fallStartY float32
+	// Where Respawn puts the player, the column first spawned on
+	spawnPos mgl32.Vec3
+
+	// Called after the player breaks a block, e.g. to spawn particles
+	OnBlockBroken func(x, y, z int, broken world.BlockType)
+	// Called each time a foot comes down while walking, with the position
+	// of the feet and the block walked on
+	OnFootstep func(pos mgl32.Vec3, ground world.BlockType)
+	// Called after the player dies and has respawned
+	OnDeath func()
 }
 
 func NewPlayer(cam *camera.Camera, w *world.World) *Player {
 	p := &Player{
-		camera:     cam,
-		world:      w,
-		PhysicsPos: cam.Position,
-		width:      0.6,
-		height:     1.8,
-		walkSpeed:  4.3,
-		jumpForce:  8.0,
+		camera:            cam,
+		world:             w,
+		PhysicsPos:        cam.Position,
+		width:             0.6,
+		height:            standHeight,
+		walkSpeed:         4.3,
+		sprintSpeed:       5.6,
+		jumpForce:         8.0,
+		SprintForwardOnly: true,
+		BridgePlacement:   true,
+		Mode:              Creative,
+		Inventory:         NewInventory(36),
+		Health:            MaxHealth,
 	}
-	p.camera.Position = p.PhysicsPos.Add(mgl32.Vec3{0, p.GetEyeHeight(), 0})
+	p.spawnOnSurface()
+	p.spawnPos = p.PhysicsPos
 	return p
 }
 
+// spawnOnSurface stands the player on the top block of the column the
+// camera starts over, centered so the body doesn't straddle columns
+func (p *Player) spawnOnSurface() {
+	x := int(math.Floor(float64(p.PhysicsPos.X())))
+	z := int(math.Floor(float64(p.PhysicsPos.Z())))
+	// An empty column reports ChunkHeight, which leaves the player at the top of the world
+	y := min(p.world.SurfaceHeight(x, z)+1, world.ChunkHeight)
+	p.PhysicsPos = mgl32.Vec3{float32(x) + 0.5, float32(y), float32(z) + 0.5}
+	p.camera.Position = p.PhysicsPos.Add(mgl32.Vec3{0, p.GetEyeHeight(), 0})
+}
+
+// Swimming tuning. Gravity is weaker in water and buoyancy slightly beats it
+// while the head is under, so a still player floats up and bobs at the
+// surface, where normal gravity takes over again.
+const (
+	waterGravity          = 6.0
+	waterBuoyancy         = 7.0
+	waterTerminalVelocity = -3.0
+	swimUpSpeed           = 3.0
+	waterSpeedFactor      = 0.5
+)
+
+// maxCollisionStep is the furthest the body moves, in blocks, between
+// collision checks
+const maxCollisionStep = 0.4
+
 func (p *Player) Update(deltaTime float32) {
 	const gravity = 25.0
 	const terminalVelocity = -50.0
 
+	// Don't fall through terrain that hasn't arrived yet (login, teleport)
+	p.waitingForTerrain = !p.world.IsChunkReady(p.PhysicsPos[0], p.PhysicsPos[2])
+	if p.waitingForTerrain {
+		p.velocity = mgl32.Vec3{0, 0, 0}
+		p.falling = false
+		p.camera.Position = p.PhysicsPos.Add(mgl32.Vec3{0, p.GetEyeHeight(), 0})
+		p.UpdateTarget()
+		return
+	}
+
 	// Anti-stuck mechanism
 	if p.checkCollision(p.PhysicsPos) {
 		p.PhysicsPos[1] += 4.0 * deltaTime
@@ -59,22 +168,54 @@ func (p *Player) Update(deltaTime float32) {
 		p.grounded = false
 	}
 
-	// Apply velocity
-	movement := p.velocity.Mul(deltaTime)
-	newPos := p.PhysicsPos.Add(movement)
+	// Only creative players fly
+	if p.Mode != Creative {
+		p.flying = false
+	}
 
-	// Collision detection
-	finalPos := p.handleCollision(newPos, &p.velocity)
-	p.PhysicsPos = finalPos
+	// Apply velocity in sub-steps no longer than maxCollisionStep, so a fast
+	// fall at a low frame rate can't pass through a thin floor
+	descending := p.velocity[1] < 0
+	steps := max(1, int(math.Ceil(float64(p.velocity.Len()*deltaTime/maxCollisionStep))))
+	stepTime := deltaTime / float32(steps)
+	againstWall := false
+	for i := 0; i < steps; i++ {
+		newPos := p.PhysicsPos.Add(p.velocity.Mul(stepTime))
+		p.PhysicsPos = p.handleCollision(newPos, &p.velocity)
+		againstWall = againstWall || p.againstWall
+	}
+	p.againstWall = againstWall
 
 	// Check if grounded
 	p.grounded = p.isGrounded()
+	p.inWater = p.isInWater()
+	p.headInWater = p.inWater && world.IsLiquid(p.world.GetBlock(
+		int(math.Floor(float64(p.PhysicsPos[0]))),
+		int(math.Floor(float64(p.PhysicsPos[1]+p.GetEyeHeight()))),
+		int(math.Floor(float64(p.PhysicsPos[2])))))
+
+	// Flying down onto the ground lands
+	if p.flying && descending && p.grounded {
+		p.flying = false
+	}
+	p.trackFall()
 
 	// Apply gravity
-	if !p.grounded {
-		p.velocity[1] -= gravity * deltaTime
-		if p.velocity[1] < terminalVelocity {
-			p.velocity[1] = terminalVelocity
+	if p.flying {
+		// Vertical speed comes from Fly and eases off like walking does
+		p.velocity[1] *= max(0, 1-flyFriction*deltaTime)
+	} else if !p.grounded {
+		fallLimit := float32(terminalVelocity)
+		if p.headInWater {
+			p.velocity[1] += (waterBuoyancy - waterGravity) * deltaTime
+		} else {
+			p.velocity[1] -= gravity * deltaTime
+		}
+		if p.inWater {
+			fallLimit = waterTerminalVelocity
+		}
+		if p.velocity[1] < fallLimit {
+			p.velocity[1] = fallLimit
 		}
 	} else {
 		// velocity is zero when grounded to prevent accumulation
@@ -85,7 +226,11 @@ func (p *Player) Update(deltaTime float32) {
 
 	// Damping
 	friction := float32(10.0)
-	if !p.grounded {
+	if p.flying {
+		friction = flyFriction
+	} else if p.inWater {
+		friction = 4.0 // Water drag
+	} else if !p.grounded {
 		friction = 1.0 // Low friction in air (air control)
 	}
 
@@ -108,7 +253,12 @@ func (p *Player) Update(deltaTime float32) {
 	horizontalSpeed := float32(math.Sqrt(float64(p.velocity[0]*p.velocity[0] + p.velocity[2]*p.velocity[2])))
 
 	if p.grounded && horizontalSpeed > 0.1 {
+		previous := p.walkingTime
 		p.walkingTime += deltaTime * 10.0
+		// A step lands every half bob cycle
+		if math.Floor(float64(p.walkingTime)/math.Pi) > math.Floor(float64(previous)/math.Pi) {
+			p.footstep()
+		}
 	} else {
 		p.walkingTime = 0
 	}
@@ -116,15 +266,57 @@ func (p *Player) Update(deltaTime float32) {
 	bobOffsetY := float32(math.Sin(float64(p.walkingTime))) * 0.1
 	bobOffsetX := float32(math.Sin(float64(p.walkingTime/2.0))) * 0.05
 
-	p.camera.Position = p.PhysicsPos.Add(mgl32.Vec3{0, p.GetEyeHeight(), 0})
+	p.stepOffset = min(0, p.stepOffset+stepEaseSpeed*deltaTime)
+	p.camera.Position = p.PhysicsPos.Add(mgl32.Vec3{0, p.GetEyeHeight() + p.stepOffset, 0})
 
 	p.camera.Position[1] += bobOffsetY
 	sway := p.camera.Right.Mul(bobOffsetX)
 	p.camera.Position = p.camera.Position.Add(sway)
 
+	if p.swingTime > 0 {
+		p.swingTime -= deltaTime
+	}
+
 	p.UpdateTarget()
 }
 
+// footstep reports a step on the block under the middle of the feet
+func (p *Player) footstep() {
+	if p.OnFootstep == nil {
+		return
+	}
+	ground := p.world.GetBlock(
+		int(math.Floor(float64(p.PhysicsPos[0]))),
+		int(math.Floor(float64(p.PhysicsPos[1])))-1,
+		int(math.Floor(float64(p.PhysicsPos[2]))))
+	if ground == world.BlockAir {
+		return // Standing on the edge of a block the center is past
+	}
+	p.OnFootstep(p.PhysicsPos, ground)
+}
+
+// swingDuration is how long one break/place arm swing lasts, in seconds
+const swingDuration = 0.25
+
+// WalkPhase is the view-bob phase, 0 while standing still
+func (p *Player) WalkPhase() float32 {
+	return p.walkingTime
+}
+
+// SwingProgress runs from 0 to 1 over an arm swing, 0 when idle
+func (p *Player) SwingProgress() float32 {
+	if p.swingTime <= 0 {
+		return 0
+	}
+	return 1 - p.swingTime/swingDuration
+}
+
+// IsWaitingForTerrain reports whether the player is being held in place
+// because the chunk underfoot isn't loaded yet
+func (p *Player) IsWaitingForTerrain() bool {
+	return p.waitingForTerrain
+}
+
 func (p *Player) UpdateTarget() {
 	hit, x, y, z, face := p.Raycast(5.0)
 	if hit {
@@ -132,6 +324,7 @@ func (p *Player) UpdateTarget() {
 			Hit:  true,
 			Pos:  mgl32.Vec3{float32(x), float32(y), float32(z)},
 			Face: face,
+			Type: p.world.GetBlock(x, y, z),
 		}
 	} else {
 		p.target = TargetBlock{}
@@ -145,44 +338,186 @@ func (p *Player) TargetBlock() TargetBlock {
 func (p *Player) Move(direction mgl32.Vec3, deltaTime float32) {
 	if direction.Len() > 0 {
 		accel := float32(60.0)
-		if !p.grounded {
+		if !p.grounded && !p.flying {
 			accel = 10.0 // Slower acceleration in air
 		}
 
 		p.velocity = p.velocity.Add(direction.Mul(accel * deltaTime))
 
+		maxSpeed := p.walkSpeed
+		if p.flying {
+			maxSpeed = flySpeed
+			if p.sprinting {
+				maxSpeed = flySprintSpeed
+			}
+		} else if p.crouching {
+			maxSpeed = crouchSpeed
+		} else if p.canSprint(direction) {
+			maxSpeed = p.sprintSpeed
+		}
+		if p.inWater {
+			maxSpeed *= waterSpeedFactor
+		}
+
 		flatVel := mgl32.Vec3{p.velocity[0], 0, p.velocity[2]}
-		if flatVel.Len() > p.walkSpeed {
-			flatVel = flatVel.Normalize().Mul(p.walkSpeed)
+		if flatVel.Len() > maxSpeed {
+			flatVel = flatVel.Normalize().Mul(maxSpeed)
 			p.velocity[0] = flatVel[0]
 			p.velocity[2] = flatVel[2]
 		}
 	}
 }
 
+func (p *Player) SetSprinting(sprinting bool) {
+	p.sprinting = sprinting
+}
+
+// Body heights and speed while crouched
+const (
+	standHeight  = 1.8
+	crouchHeight = 1.5
+	crouchSpeed  = 1.3
+)
+
+// SetCrouching crouches or stands up. Crouching lowers the body and eyes,
+// slows movement and stops the player walking off edges. Standing up waits
+// until there is headroom, so the player stays crouched under low ceilings.
+func (p *Player) SetCrouching(crouching bool) {
+	if crouching == p.crouching {
+		return
+	}
+	if crouching {
+		p.crouching = true
+		p.height = crouchHeight
+		return
+	}
+
+	p.height = standHeight
+	if p.checkCollision(p.PhysicsPos) {
+		p.height = crouchHeight
+		return
+	}
+	p.crouching = false
+}
+
+func (p *Player) IsCrouching() bool {
+	return p.crouching
+}
+
+func (p *Player) canSprint(direction mgl32.Vec3) bool {
+	const sprintConeDeg = 45.0
+
+	if !p.sprinting {
+		return false
+	}
+	if !p.SprintForwardOnly {
+		return true
+	}
+
+	forward := mgl32.Vec3{p.camera.Front[0], 0, p.camera.Front[2]}
+	flatDir := mgl32.Vec3{direction[0], 0, direction[2]}
+	if forward.Len() == 0 || flatDir.Len() == 0 {
+		return false
+	}
+
+	cosAngle := forward.Normalize().Dot(flatDir.Normalize())
+	return cosAngle >= float32(math.Cos(float64(mgl32.DegToRad(sprintConeDeg))))
+}
+
+// Creative flight tuning
+const (
+	flySpeed         = 11.0
+	flySprintSpeed   = 22.0
+	flyVerticalSpeed = 8.0
+	flyFriction      = 6.0
+)
+
+// SetFlying starts or stops creative flight. Ignored outside creative mode.
+func (p *Player) SetFlying(flying bool) {
+	if flying && p.Mode != Creative {
+		return
+	}
+	p.flying = flying
+	if flying {
+		p.SetCrouching(false)
+		p.grounded = false
+		p.velocity[1] = 0
+	}
+}
+
+func (p *Player) IsFlying() bool {
+	return p.flying
+}
+
+// Fly moves a flying player up (vertical 1) or down (-1), 0 to hover
+func (p *Player) Fly(vertical float32) {
+	if !p.flying || vertical == 0 {
+		return
+	}
+	p.velocity[1] = vertical * flyVerticalSpeed
+}
+
+// Jump jumps off the ground. In water it swims up instead, and pushing
+// against a block at the surface gives a full jump to climb out.
 func (p *Player) Jump() {
-	if p.grounded {
+	if p.grounded || (p.inWater && !p.headInWater && p.againstWall) {
 		p.velocity[1] = p.jumpForce
 		p.grounded = false // Instant feedback
+		return
+	}
+	if p.inWater && p.velocity[1] < swimUpSpeed {
+		p.velocity[1] = swimUpSpeed
 	}
 }
 
+// IsInWater reports whether any part of the player's body is in water
+func (p *Player) IsInWater() bool {
+	return p.inWater
+}
+
 func (p *Player) handleCollision(newPos mgl32.Vec3, velocity *mgl32.Vec3) mgl32.Vec3 {
+	// Crouching on the ground never steps off an edge. Each axis is checked
+	// on its own so the player can still shuffle along a ledge.
+	edgeGuard := p.crouching && p.grounded
+	p.againstWall = false
+
 	// Simple AABB collision
 	testPos := mgl32.Vec3{newPos[0], p.PhysicsPos[1], p.PhysicsPos[2]}
-	if p.checkCollision(testPos) {
+	hitX := p.checkCollision(testPos)
+	stepped := hitX && p.tryStepUp(testPos)
+	if stepped {
+		hitX = false
+		newPos[1] = p.PhysicsPos[1]
+	}
+	if hitX || (edgeGuard && !p.isGroundedAt(testPos)) {
 		newPos[0] = p.PhysicsPos[0] // Revert X
 		velocity[0] = 0             // Stop X momentum
+		p.againstWall = p.againstWall || hitX
 	}
 
 	testPos = mgl32.Vec3{newPos[0], p.PhysicsPos[1], newPos[2]}
-	if p.checkCollision(testPos) {
+	hitZ := p.checkCollision(testPos)
+	// One step per frame, a corner of two ledges would otherwise climb two
+	if hitZ && !stepped && p.tryStepUp(testPos) {
+		hitZ = false
+		newPos[1] = p.PhysicsPos[1]
+	}
+	if hitZ || (edgeGuard && !p.isGroundedAt(testPos)) {
 		newPos[2] = p.PhysicsPos[2] // Revert Z
 		velocity[2] = 0             // Stop Z momentum
+		p.againstWall = p.againstWall || hitZ
 	}
 
 	testPos = mgl32.Vec3{newPos[0], newPos[1], newPos[2]}
 	if p.checkCollision(testPos) {
+		if velocity[1] > 0 {
+			// Ceiling bonk: stop rising and start falling next frame.
+			// Grounded is left alone so the player can't re-jump off the ceiling.
+			newPos[1] = p.ceilingSnap(newPos)
+			velocity[1] = 0
+			return newPos
+		}
+
 		newPos[1] = p.PhysicsPos[1]
 
 		if velocity[1] < 0 {
@@ -195,18 +530,54 @@ func (p *Player) handleCollision(newPos mgl32.Vec3, velocity *mgl32.Vec3) mgl32.
 	return newPos
 }
 
+// stepEaseSpeed is how fast, in blocks per second, the camera catches up
+// after stepping up a ledge
+const stepEaseSpeed = 8.0
+
+// tryStepUp lifts a grounded player onto a one block ledge blocking a move
+// to pos, if the body fits on top. Returns true if the player stepped up.
+// The camera lags behind and eases up so the step isn't a jump cut.
+func (p *Player) tryStepUp(pos mgl32.Vec3) bool {
+	if !p.grounded || p.flying {
+		return false
+	}
+	stepY := float32(math.Floor(float64(p.PhysicsPos[1]))) + 1
+	raised := mgl32.Vec3{pos[0], stepY, pos[2]}
+	// Also rules out a one block gap under a ceiling
+	if p.checkCollision(raised) || p.checkCollision(mgl32.Vec3{p.PhysicsPos[0], stepY, p.PhysicsPos[2]}) {
+		return false
+	}
+	p.stepOffset -= stepY - p.PhysicsPos[1]
+	p.PhysicsPos[1] = stepY
+	return true
+}
+
+// ceilingSnap moves the head flush against the block above instead of
+// reverting to the previous height, which left a gap that felt sticky
+func (p *Player) ceilingSnap(newPos mgl32.Vec3) float32 {
+	const epsilon = 0.001
+
+	ceiling := float32(math.Floor(float64(newPos[1] + p.height)))
+	snapped := ceiling - p.height - epsilon
+
+	if snapped < p.PhysicsPos[1] {
+		return p.PhysicsPos[1]
+	}
+
+	testPos := mgl32.Vec3{newPos[0], snapped, newPos[2]}
+	if p.checkCollision(testPos) {
+		return p.PhysicsPos[1]
+	}
+	return snapped
+}
+
 func (p *Player) checkCollision(pos mgl32.Vec3) bool {
-	minX := int(math.Floor(float64(pos[0] - p.width/2)))
-	maxX := int(math.Floor(float64(pos[0] + p.width/2)))
-	minY := int(math.Floor(float64(pos[1])))
-	maxY := int(math.Floor(float64(pos[1] + p.height)))
-	minZ := int(math.Floor(float64(pos[2] - p.width/2)))
-	maxZ := int(math.Floor(float64(pos[2] + p.width/2)))
+	minX, minY, minZ, maxX, maxY, maxZ := p.bounds(pos).BlockRange()
 
 	for x := minX; x <= maxX; x++ {
 		for y := minY; y <= maxY; y++ {
 			for z := minZ; z <= maxZ; z++ {
-				if p.world.GetBlock(x, y, z) != world.BlockAir {
+				if world.IsSolid(p.world.GetBlock(x, y, z)) {
 					return true
 				}
 			}
@@ -216,15 +587,18 @@ func (p *Player) checkCollision(pos mgl32.Vec3) bool {
 }
 
 func (p *Player) isGrounded() bool {
-	minX := int(math.Floor(float64(p.PhysicsPos[0] - p.width/2)))
-	maxX := int(math.Floor(float64(p.PhysicsPos[0] + p.width/2)))
-	minZ := int(math.Floor(float64(p.PhysicsPos[2] - p.width/2)))
-	maxZ := int(math.Floor(float64(p.PhysicsPos[2] + p.width/2)))
+	return p.isGroundedAt(p.PhysicsPos)
+}
+
+// isGroundedAt reports whether a body with feet at pos would stand on a
+// solid block
+func (p *Player) isGroundedAt(pos mgl32.Vec3) bool {
+	minX, minY, minZ, maxX, _, maxZ := p.bounds(pos).BlockRange()
 
-	checkY := int(math.Floor(float64(p.PhysicsPos[1]))) - 1
+	checkY := minY - 1
 	for x := minX; x <= maxX; x++ {
 		for z := minZ; z <= maxZ; z++ {
-			if p.world.GetBlock(x, checkY, z) != world.BlockAir {
+			if world.IsSolid(p.world.GetBlock(x, checkY, z)) {
 				return true
 			}
 		}
@@ -233,74 +607,201 @@ func (p *Player) isGrounded() bool {
 	return false
 }
 
-// Raycast to find the block the player is looking at
+// isInWater reports whether any block the body overlaps is a liquid
+func (p *Player) isInWater() bool {
+	minX, minY, minZ, maxX, maxY, maxZ := p.bounds(p.PhysicsPos).BlockRange()
+
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			for z := minZ; z <= maxZ; z++ {
+				if world.IsLiquid(p.world.GetBlock(x, y, z)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// bounds is the player's collision box with feet at pos
+func (p *Player) bounds(pos mgl32.Vec3) physics.AABB {
+	return physics.BodyAABB(pos, p.width, p.height)
+}
+
+// Raycast finds the block the player is looking at. It walks the voxel
+// grid with Amanatides-Woo DDA, so every cell the ray crosses is visited
+// exactly once and face is the side the ray entered through.
 func (p *Player) Raycast(maxDistance float32) (hit bool, x, y, z int, face int) {
 	pos := p.camera.Position
 	dir := p.camera.Front
-	step := float32(0.1)
-
-	for dist := float32(0); dist < maxDistance; dist += step {
-		checkPos := pos.Add(dir.Mul(dist))
-		bx := int(math.Floor(float64(checkPos[0])))
-		by := int(math.Floor(float64(checkPos[1])))
-		bz := int(math.Floor(float64(checkPos[2])))
-
-		if p.world.GetBlock(bx, by, bz) != world.BlockAir {
-			// Determine which face was hit
-			prevPos := pos.Add(dir.Mul(dist - step))
-			px := int(math.Floor(float64(prevPos[0])))
-			py := int(math.Floor(float64(prevPos[1])))
-			pz := int(math.Floor(float64(prevPos[2])))
-
-			if bx != px {
-				if bx > px {
-					face = 3 // -X
-				} else {
-					face = 2 // +X
-				}
-			} else if by != py {
-				if by > py {
-					face = 5 // -Y
-				} else {
-					face = 4 // +Y
-				}
-			} else if bz != pz {
-				if bz > pz {
-					face = 1 // -Z
-				} else {
-					face = 0 // +Z
-				}
-			}
 
-			return true, bx, by, bz, face
+	cell := [3]int{}
+	var step [3]int
+	var tMax, tDelta [3]float64
+	// Face entered when stepping along each axis in the step direction
+	var entered [3]int
+	negFaces := [3]int{3, 5, 1} // -X, -Y, -Z
+	posFaces := [3]int{2, 4, 0} // +X, +Y, +Z
+
+	for i := 0; i < 3; i++ {
+		origin := float64(pos[i])
+		d := float64(dir[i])
+		cell[i] = int(math.Floor(origin))
+
+		switch {
+		case d > 0:
+			step[i] = 1
+			tMax[i] = (float64(cell[i]+1) - origin) / d
+			tDelta[i] = 1 / d
+			entered[i] = negFaces[i]
+		case d < 0:
+			step[i] = -1
+			tMax[i] = (float64(cell[i]) - origin) / d
+			tDelta[i] = -1 / d
+			entered[i] = posFaces[i]
+		default:
+			tMax[i] = math.Inf(1)
+			tDelta[i] = math.Inf(1)
 		}
 	}
 
-	return false, 0, 0, 0, 0
+	// A camera inside a block hits it straight away
+	if world.IsSolid(p.world.GetBlock(cell[0], cell[1], cell[2])) {
+		return true, cell[0], cell[1], cell[2], 0
+	}
+
+	for {
+		// Step along whichever axis reaches its next cell boundary first
+		axis := 0
+		if tMax[1] < tMax[axis] {
+			axis = 1
+		}
+		if tMax[2] < tMax[axis] {
+			axis = 2
+		}
+		if tMax[axis] > float64(maxDistance) {
+			return false, 0, 0, 0, 0
+		}
+
+		cell[axis] += step[axis]
+		tMax[axis] += tDelta[axis]
+
+		if world.IsSolid(p.world.GetBlock(cell[0], cell[1], cell[2])) {
+			return true, cell[0], cell[1], cell[2], entered[axis]
+		}
+	}
 }
 
-func (p *Player) BreakBlock() {
+// BreakBlock removes the targeted block. Returns false if there was no
+// target or the world refused the edit.
+func (p *Player) BreakBlock() bool {
 	if !p.target.Hit {
-		return
+		return false
+	}
+
+	pos := p.target.Pos
+	x, y, z := int(pos.X()), int(pos.Y()), int(pos.Z())
+	broken := p.world.GetBlock(x, y, z)
+
+	if !p.world.SetBlock(x, y, z, world.BlockAir) {
+		return false
+	}
+	p.swingTime = swingDuration
+	if p.OnBlockBroken != nil {
+		p.OnBlockBroken(x, y, z, broken)
+	}
+
+	if p.Mode == Survival {
+		for _, drop := range world.DropsFor(broken) {
+			p.Inventory.Add(drop.Type, drop.Count)
+		}
+	}
+	return true
+}
+
+// UseBlock interacts with the targeted block. Returns true if the block
+// handled the use, in which case nothing should be placed.
+func (p *Player) UseBlock() bool {
+	if !p.target.Hit {
+		return false
 	}
 
 	pos := p.target.Pos
-	p.world.SetBlock(
-		int(pos.X()),
-		int(pos.Y()),
-		int(pos.Z()),
-		world.BlockAir,
-	)
+	return p.world.UseBlock(int(pos.X()), int(pos.Y()), int(pos.Z()))
+}
+
+// PlaceResult says whether a placement happened and, if not, why
+type PlaceResult int
+
+const (
+	Placed PlaceResult = iota
+	PlaceNoTarget
+	PlaceNothingHeld
+	PlaceBlockedByPlayer
+	PlaceOutOfWorld
+	PlaceProtected
+)
+
+// String is the player-facing reason for a denied placement
+func (r PlaceResult) String() string {
+	switch r {
+	case Placed:
+		return "Placed"
+	case PlaceNoTarget:
+		return "No block targeted"
+	case PlaceNothingHeld:
+		return "Nothing selected"
+	case PlaceBlockedByPlayer:
+		return "You're in the way"
+	case PlaceOutOfWorld:
+		return "Outside the world"
+	case PlaceProtected:
+		return "This area is protected"
+	default:
+		return "Unknown"
+	}
+}
+
+// PlaceBlock places the block held in an inventory slot, normally the
+// selected hotbar slot. In survival the block comes out of that slot.
+func (p *Player) PlaceBlock(slot int) PlaceResult {
+	x, y, z, ok := p.placementCell()
+	if !ok {
+		return PlaceNoTarget
+	}
+	held := p.Inventory.Slots[slot]
+	if held.Count == 0 || held.Type == world.BlockAir {
+		return PlaceNothingHeld
+	}
+	blockType := held.Type
+
+	if y < 0 || y >= world.ChunkHeight {
+		return PlaceOutOfWorld
+	}
+	if world.IsSolid(blockType) && p.collidesWithPlayer(float32(x), float32(y), float32(z)) {
+		return PlaceBlockedByPlayer
+	}
+
+	if !p.world.SetBlockOriented(x, y, z, blockType, p.placementAxis()) {
+		return PlaceProtected
+	}
+	if p.Mode == Survival {
+		p.Inventory.RemoveAt(slot, 1)
+	}
+	p.swingTime = swingDuration
+	return Placed
 }
 
-func (p *Player) PlaceBlock(blockType world.BlockType) {
+// placementCell is where a placed block would go: against the targeted face,
+// or failing that the bridging cell beside the block underfoot
+func (p *Player) placementCell() (x, y, z int, ok bool) {
 	if !p.target.Hit {
-		return
+		return p.bridgeCell()
 	}
 
-	x := int(p.target.Pos.X())
-	y := int(p.target.Pos.Y())
-	z := int(p.target.Pos.Z())
+	x = int(p.target.Pos.X())
+	y = int(p.target.Pos.Y())
+	z = int(p.target.Pos.Z())
 
 	switch p.target.Face {
 	case 0:
@@ -316,25 +817,87 @@ func (p *Player) PlaceBlock(blockType world.BlockType) {
 	case 5:
 		y--
 	}
+	return x, y, z, true
+}
+
+// placementAxis is the way a directional block like a log is laid: through
+// the face it is placed against, or when bridging without one, along the
+// way the player faces
+func (p *Player) placementAxis() world.Axis {
+	if p.target.Hit {
+		return world.AxisOfFace(p.target.Face)
+	}
+	front := p.camera.Front
+	x, y, z := mgl32.Abs(front.X()), mgl32.Abs(front.Y()), mgl32.Abs(front.Z())
+	switch {
+	case y >= x && y >= z:
+		return world.AxisY
+	case x >= z:
+		return world.AxisX
+	default:
+		return world.AxisZ
+	}
+}
+
+// bridgeCell finds an empty cell level with and beside a solid block the
+// player is standing on, which the look ray passes through. Only allowed
+// while crouching on the ground, and the cell always touches the supporting
+// block, so nothing can be placed floating in mid-air.
+func (p *Player) bridgeCell() (x, y, z int, ok bool) {
+	if !p.BridgePlacement || !p.crouching || !p.grounded {
+		return 0, 0, 0, false
+	}
+
+	const reach = 5.0
+	origin := p.camera.Position
+	dir := p.camera.Front
 
-	p.world.SetBlock(x, y, z, blockType)
+	minX, minY, minZ, maxX, _, maxZ := p.bounds(p.PhysicsPos).BlockRange()
+	supportY := minY - 1
+	sides := [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
 
+	best := float32(reach)
+	for sx := minX; sx <= maxX; sx++ {
+		for sz := minZ; sz <= maxZ; sz++ {
+			if !world.IsSolid(p.world.GetBlock(sx, supportY, sz)) {
+				continue
+			}
+			for _, side := range sides {
+				cx, cz := sx+side[0], sz+side[1]
+				if world.IsSolid(p.world.GetBlock(cx, supportY, cz)) {
+					continue
+				}
+				dist, hit := physics.BlockAABB(cx, supportY, cz).RayDistance(origin, dir)
+				if hit && dist < best {
+					best = dist
+					x, y, z, ok = cx, supportY, cz, true
+				}
+			}
+		}
+	}
+	return x, y, z, ok
 }
 
 func (p *Player) collidesWithPlayer(x, y, z float32) bool {
-	px := p.PhysicsPos.X()
-	py := p.PhysicsPos.Y()
-	pz := p.PhysicsPos.Z()
-
-	return mgl32.Abs(px-x) < p.width &&
-		py < y+p.height &&
-		py+p.height > y &&
-		mgl32.Abs(pz-z) < p.width
+	block := physics.BlockAABB(int(x), int(y), int(z))
+	return p.bounds(p.PhysicsPos).Intersects(block)
 }
+
 func (p *Player) GetEyeHeight() float32 {
 	return p.height - 0.2
 }
 
+// Teleport moves the player's feet to pos, stopping all movement. A fall in
+// progress is forgotten rather than hurting on arrival.
+func (p *Player) Teleport(pos mgl32.Vec3) {
+	p.PhysicsPos = pos
+	p.camera.Position = pos.Add(mgl32.Vec3{0, p.GetEyeHeight(), 0})
+
+	p.velocity = mgl32.Vec3{0, 0, 0}
+	p.grounded = false
+	p.falling = false
+}
+
 func (p *Player) TeleportToCamera() {
 	eyeOffset := mgl32.Vec3{0, p.GetEyeHeight(), 0}
 
@@ -342,4 +905,5 @@ func (p *Player) TeleportToCamera() {
 
 	p.velocity = mgl32.Vec3{0, 0, 0}
 	p.grounded = false
+	p.falling = false
 }
@@ -0,0 +1,233 @@
+package player
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"voxel-game/internal/camera"
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// newTestPlayer puts a creative player in an empty headless world, floating
+// at the top until a test moves them
+func newTestPlayer(t *testing.T) (*Player, *world.World) {
+	t.Helper()
+	w := world.NewHeadlessWorld(1)
+	return NewPlayer(camera.NewCamera(1280, 720), w), w
+}
+
+// fill sets every block in the box from min to max, inclusive
+func fill(w *world.World, minX, minY, minZ, maxX, maxY, maxZ int, blockType world.BlockType) {
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			for z := minZ; z <= maxZ; z++ {
+				w.SetBlock(x, y, z, blockType)
+			}
+		}
+	}
+}
+
+func TestFallLandsOnThinFloorAtLowFrameRate(t *testing.T) {
+	p, w := newTestPlayer(t)
+	fill(w, -1, 10, -1, 1, 10, 1, world.BlockStone)
+	p.Teleport(mgl32.Vec3{0.5, 111, 0.5})
+
+	// 4 fps, fast enough near terminal velocity to cross the floor in a frame
+	for i := 0; i < 40; i++ {
+		p.Update(0.25)
+	}
+
+	if y := p.PhysicsPos.Y(); y < 11 || y > 11.01 {
+		t.Errorf("feet at y %v, want on top of the floor at 11", y)
+	}
+	if !p.grounded {
+		t.Error("not grounded after landing")
+	}
+}
+
+func TestJumpIntoCeiling(t *testing.T) {
+	p, w := newTestPlayer(t)
+	fill(w, -1, 10, -1, 1, 10, 1, world.BlockStone)
+	// A two block gap, just room to stand
+	const ceiling = 13
+	fill(w, -1, ceiling, -1, 1, ceiling, 1, world.BlockStone)
+	p.Teleport(mgl32.Vec3{0.5, 11, 0.5})
+	p.Update(1.0 / 60)
+	if !p.grounded {
+		t.Fatal("not standing on the floor")
+	}
+
+	// Rise until the next step would put the head in the ceiling, then take
+	// that step
+	p.Jump()
+	for i := 0; i < 60 && p.velocity.Y() > 0; i++ {
+		next := p.PhysicsPos.Add(p.velocity.Mul(1.0 / 60))
+		if next.Y()+p.height < ceiling {
+			p.Update(1.0 / 60)
+			continue
+		}
+
+		// isGrounded counts the floor until the feet clear a whole block,
+		// only what the ceiling hit does to grounded matters here
+		p.grounded = false
+		velocity := p.velocity
+		pos := p.handleCollision(next, &velocity)
+		if velocity.Y() != 0 {
+			t.Errorf("vertical velocity %v after hitting the ceiling, want 0", velocity.Y())
+		}
+		if p.grounded {
+			t.Error("hitting the ceiling set grounded")
+		}
+		if pos.Y()+p.height > ceiling {
+			t.Errorf("head at %v, inside the ceiling at %d", pos.Y()+p.height, ceiling)
+		}
+		return
+	}
+	t.Fatal("jump never reached the ceiling")
+}
+
+func TestWaitsForTerrainAtUnloadedSpawn(t *testing.T) {
+	p, _ := newTestPlayer(t)
+	// Well outside the loaded chunks around the origin
+	spawn := mgl32.Vec3{1000.5, 80, 1000.5}
+	p.Teleport(spawn)
+
+	for i := 0; i < 30; i++ {
+		p.Update(1.0 / 30)
+	}
+
+	if !p.IsWaitingForTerrain() {
+		t.Error("not waiting for the missing chunk")
+	}
+	if p.PhysicsPos != spawn {
+		t.Errorf("moved to %v while the chunk was missing, want to stay at %v", p.PhysicsPos, spawn)
+	}
+	if p.velocity != (mgl32.Vec3{}) {
+		t.Errorf("velocity %v while waiting, want none", p.velocity)
+	}
+}
+
+func TestWaitsForSavedChunkAtRenderDistance(t *testing.T) {
+	dir := t.TempDir()
+	distance := world.DefaultRenderDistance
+	// A platform high above any generated terrain, in a chunk at the render
+	// distance edge from spawn, in an otherwise empty world
+	edited := world.NewHeadlessWorld(distance)
+	if err := edited.Load(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load of an empty dir = %v, want ErrNotExist", err)
+	}
+	const platform = 200
+	baseX := distance * world.ChunkSize
+	fill(edited, baseX+4, platform, 4, baseX+11, platform, 11, world.BlockStone)
+	if err := edited.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	w := world.NewHeadlessWorld(0)
+	if err := w.Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(w.Close)
+	if got := w.RenderDistance(); got != distance {
+		t.Fatalf("render distance %d, want %d", got, distance)
+	}
+	p := NewPlayer(camera.NewCamera(1280, 720), w)
+	login := mgl32.Vec3{float32(baseX) + 8.5, platform + 10, 8.5}
+	p.Teleport(login)
+
+	for i := 0; i < 30; i++ {
+		p.Update(1.0 / 30)
+	}
+	if !p.IsWaitingForTerrain() {
+		t.Fatal("not waiting for the saved chunk")
+	}
+	if p.PhysicsPos != login {
+		t.Fatalf("moved to %v before the saved chunk loaded, want to stay at %v", p.PhysicsPos, login)
+	}
+
+	w.UpdateChunksAsync(p.PhysicsPos.X(), p.PhysicsPos.Z())
+	w.IntegrateReadyChunks()
+	for i := 0; i < 60; i++ {
+		p.Update(1.0 / 30)
+	}
+	if p.IsWaitingForTerrain() {
+		t.Fatal("still waiting after the saved chunk loaded")
+	}
+	// A blocked fall stops short of the floor by up to a step, rather than
+	// on it exactly, so only check it stands on the platform
+	if y := p.PhysicsPos.Y(); !p.grounded || y < platform+1 || y >= platform+2 {
+		t.Errorf("feet at y %v, grounded %v, want standing on the saved platform at %d",
+			y, p.grounded, platform+1)
+	}
+	// Regenerated terrain would be solid down here
+	if got := w.GetBlock(baseX+8, 5, 8); got != world.BlockAir {
+		t.Errorf("block under the platform is %v, want air from the save", got)
+	}
+}
+
+func TestRaycastFaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     mgl32.Vec3
+		block   [3]int
+		face    int
+		missing bool
+	}{
+		{"+X", mgl32.Vec3{1, 0, 0}, [3]int{3, 20, 0}, 3, false},
+		{"-X", mgl32.Vec3{-1, 0, 0}, [3]int{-3, 20, 0}, 2, false},
+		{"+Y", mgl32.Vec3{0, 1, 0}, [3]int{0, 23, 0}, 5, false},
+		{"-Y", mgl32.Vec3{0, -1, 0}, [3]int{0, 17, 0}, 4, false},
+		{"+Z", mgl32.Vec3{0, 0, 1}, [3]int{0, 20, 3}, 1, false},
+		{"-Z", mgl32.Vec3{0, 0, -1}, [3]int{0, 20, -3}, 0, false},
+		// Crosses z = 2 at x = 3.5, then x = 4 at z = 2.25
+		{"diagonal through -Z", mgl32.Vec3{1, 0, 0.5}, [3]int{3, 20, 2}, 1, false},
+		{"diagonal through -X", mgl32.Vec3{1, 0, 0.5}, [3]int{4, 20, 2}, 3, false},
+		{"out of reach", mgl32.Vec3{1, 0, 0}, [3]int{9, 20, 0}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, w := newTestPlayer(t)
+			w.SetBlock(tt.block[0], tt.block[1], tt.block[2], world.BlockStone)
+			p.camera.Position = mgl32.Vec3{0.5, 20.5, 0.5}
+			p.camera.Front = tt.dir.Normalize()
+
+			hit, x, y, z, face := p.Raycast(5)
+			if tt.missing {
+				if hit {
+					t.Errorf("hit %d, %d, %d beyond reach", x, y, z)
+				}
+				return
+			}
+			if !hit {
+				t.Fatal("missed the block")
+			}
+			if [3]int{x, y, z} != tt.block || face != tt.face {
+				t.Errorf("hit %d, %d, %d face %d, want %v face %d", x, y, z, face, tt.block, tt.face)
+			}
+		})
+	}
+}
+
+func TestSurvivalPlaceUsesSelectedSlot(t *testing.T) {
+	p, w := newTestPlayer(t)
+	p.Mode = Survival
+	w.SetBlock(5, 10, 5, world.BlockStone)
+	p.Teleport(mgl32.Vec3{0.5, 11, 0.5})
+	p.target = TargetBlock{Hit: true, Pos: mgl32.Vec3{5, 10, 5}, Face: 4, Type: world.BlockStone}
+	p.Inventory.Slots[0] = ItemStack{Type: world.BlockDirt, Count: 5}
+	p.Inventory.Slots[2] = ItemStack{Type: world.BlockDirt, Count: 3}
+
+	if result := p.PlaceBlock(2); result != Placed {
+		t.Fatalf("PlaceBlock = %v, want Placed", result)
+	}
+	if got := w.GetBlock(5, 11, 5); got != world.BlockDirt {
+		t.Errorf("placed %v, want dirt", got)
+	}
+	if p.Inventory.Slots[0].Count != 5 || p.Inventory.Slots[2].Count != 2 {
+		t.Errorf("slot counts %d and %d, want 5 and 2 with the selected slot used",
+			p.Inventory.Slots[0].Count, p.Inventory.Slots[2].Count)
+	}
+}
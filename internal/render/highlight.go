@@ -0,0 +1,67 @@
+package render
+
+import "strings"
+
+// HighlightMode selects how the targeted block is marked. Modes combine as flags.
+type HighlightMode uint8
+
+const (
+	HighlightOutline HighlightMode = 1 << iota // Wire box around the block
+	HighlightShade                             // Translucent overlay on the whole block
+	HighlightFace                              // Translucent overlay on the targeted face
+)
+
+// highlightCycle is the order the highlight key steps through
+var highlightCycle = []HighlightMode{
+	HighlightOutline,
+	HighlightShade,
+	HighlightFace,
+	HighlightOutline | HighlightShade,
+	HighlightOutline | HighlightFace,
+}
+
+var highlightNames = []struct {
+	mode HighlightMode
+	name string
+}{
+	{HighlightOutline, "outline"},
+	{HighlightShade, "shade"},
+	{HighlightFace, "face"},
+}
+
+// ParseHighlightMode reads a mode like "outline+face". Unknown or empty input
+// falls back to the outline.
+func ParseHighlightMode(s string) HighlightMode {
+	var mode HighlightMode
+	for _, part := range strings.Split(s, "+") {
+		for _, n := range highlightNames {
+			if strings.TrimSpace(part) == n.name {
+				mode |= n.mode
+			}
+		}
+	}
+	if mode == 0 {
+		return HighlightOutline
+	}
+	return mode
+}
+
+func (m HighlightMode) String() string {
+	parts := make([]string, 0, len(highlightNames))
+	for _, n := range highlightNames {
+		if m&n.mode != 0 {
+			parts = append(parts, n.name)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// Next returns the mode after m in the cycle order
+func (m HighlightMode) Next() HighlightMode {
+	for i, mode := range highlightCycle {
+		if mode == m {
+			return highlightCycle[(i+1)%len(highlightCycle)]
+		}
+	}
+	return highlightCycle[0]
+}
@@ -0,0 +1,234 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"voxel-game/internal/camera"
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Break particle tuning
+const (
+	particleGravity     = 16.0
+	particleMinLife     = 0.5
+	particleMaxLife     = 1.0
+	particleMinSize     = 0.08
+	particleMaxSize     = 0.16
+	particleSpread      = 2.5 // Max horizontal launch speed
+	particleMinUpSpeed  = 2.0
+	particleMaxUpSpeed  = 5.0
+	particleSubdivision = 4 // Each particle shows 1/4 x 1/4 of the block's tile
+
+	// Floats per particle in the instance buffer: center (3), size (1), UV origin (2)
+	particleInstanceFloats = 6
+)
+
+type particle struct {
+	pos      mgl32.Vec3
+	velocity mgl32.Vec3
+	size     float32
+	life     float32 // Seconds left
+	uv       [2]float32
+}
+
+// ParticleSystem simulates small textured cubes and draws them all with one
+// instanced draw call. Particles past the cap evict the oldest.
+type ParticleSystem struct {
+	program uint32
+	vao     uint32
+	cubeVBO uint32
+	instVBO uint32
+	atlasID uint32
+
+	world *world.World // Particles come to rest on solid blocks
+
+	particles    []particle // Oldest first
+	maxParticles int
+	instances    []float32 // Reused every frame
+
+	// Color the textures are multiplied by, normally following the daylight
+	Light mgl32.Vec3
+}
+
+func NewParticleSystem(w *world.World, atlasID uint32, maxParticles int) (*ParticleSystem, error) {
+	program, err := createShaderProgram("internal/render/shaders/particle_vertex.glsl", "internal/render/shaders/particle_fragment.glsl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create particle shader: %w", err)
+	}
+
+	ps := &ParticleSystem{
+		program:      program,
+		atlasID:      atlasID,
+		world:        w,
+		maxParticles: maxParticles,
+		particles:    make([]particle, 0, maxParticles),
+		instances:    make([]float32, 0, maxParticles*particleInstanceFloats),
+		Light:        mgl32.Vec3{1, 1, 1},
+	}
+
+	gl.GenVertexArrays(1, &ps.vao)
+	gl.GenBuffers(1, &ps.cubeVBO)
+	gl.GenBuffers(1, &ps.instVBO)
+	gl.BindVertexArray(ps.vao)
+
+	// Unit cube: position (3), local UV (2), shade (1)
+	cube := particleCube()
+	gl.BindBuffer(gl.ARRAY_BUFFER, ps.cubeVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, len(cube)*4, gl.Ptr(cube), gl.STATIC_DRAW)
+	stride := int32(6 * 4)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointer(2, 1, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+
+	// Per-instance data, refilled every frame
+	gl.BindBuffer(gl.ARRAY_BUFFER, ps.instVBO)
+	gl.BufferData(gl.ARRAY_BUFFER, maxParticles*particleInstanceFloats*4, nil, gl.STREAM_DRAW)
+	stride = int32(particleInstanceFloats * 4)
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribPointer(3, 4, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.VertexAttribDivisor(3, 1)
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointer(4, 2, gl.FLOAT, false, stride, gl.PtrOffset(4*4))
+	gl.VertexAttribDivisor(4, 1)
+
+	gl.BindVertexArray(0)
+	return ps, nil
+}
+
+// particleCube builds a unit cube centered on the origin, CCW from outside,
+// with fixed per-face shading so the cubes read as 3D without lighting
+func particleCube() []float32 {
+	// Corners of each face in bottom-left, bottom-right, top-right, top-left order
+	faces := [6]struct {
+		corners [4][3]float32
+		shade   float32
+	}{
+		{[4][3]float32{{-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1}}, 0.85},     // Front (+Z)
+		{[4][3]float32{{1, -1, -1}, {-1, -1, -1}, {-1, 1, -1}, {1, 1, -1}}, 0.85}, // Back (-Z)
+		{[4][3]float32{{1, -1, 1}, {1, -1, -1}, {1, 1, -1}, {1, 1, 1}}, 0.7},      // Right (+X)
+		{[4][3]float32{{-1, -1, -1}, {-1, -1, 1}, {-1, 1, 1}, {-1, 1, -1}}, 0.7},  // Left (-X)
+		{[4][3]float32{{-1, 1, 1}, {1, 1, 1}, {1, 1, -1}, {-1, 1, -1}}, 1.0},      // Top (+Y)
+		{[4][3]float32{{-1, -1, -1}, {1, -1, -1}, {1, -1, 1}, {-1, -1, 1}}, 0.55}, // Bottom (-Y)
+	}
+	uvs := [4][2]float32{{0, 1}, {1, 1}, {1, 0}, {0, 0}}
+
+	verts := make([]float32, 0, 36*6)
+	for _, f := range faces {
+		for _, i := range [6]int{0, 1, 2, 0, 2, 3} {
+			c := f.corners[i]
+			verts = append(verts, c[0]*0.5, c[1]*0.5, c[2]*0.5, uvs[i][0], uvs[i][1], f.shade)
+		}
+	}
+	return verts
+}
+
+// Spawn bursts count particles out of the block centered at pos, each
+// showing a random piece of the block's side texture
+func (ps *ParticleSystem) Spawn(pos mgl32.Vec3, blockType world.BlockType, count int) {
+	count = min(count, ps.maxParticles)
+	if over := len(ps.particles) + count - ps.maxParticles; over > 0 {
+		ps.particles = append(ps.particles[:0], ps.particles[over:]...)
+	}
+
+	u, v := world.GetBlockUVs(blockType, 0, 0)
+	subU := float32(world.TileSize / world.TextureWidth / particleSubdivision)
+	subV := float32(world.TileSize / world.TextureHeight / particleSubdivision)
+
+	for i := 0; i < count; i++ {
+		offset := mgl32.Vec3{rand.Float32() - 0.5, rand.Float32() - 0.5, rand.Float32() - 0.5}.Mul(0.6)
+		angle := rand.Float64() * 2 * math.Pi
+		speed := rand.Float32() * particleSpread
+		ps.particles = append(ps.particles, particle{
+			pos: pos.Add(offset),
+			velocity: mgl32.Vec3{
+				float32(math.Cos(angle)) * speed,
+				particleMinUpSpeed + rand.Float32()*(particleMaxUpSpeed-particleMinUpSpeed),
+				float32(math.Sin(angle)) * speed,
+			},
+			size: particleMinSize + rand.Float32()*(particleMaxSize-particleMinSize),
+			life: particleMinLife + rand.Float32()*(particleMaxLife-particleMinLife),
+			uv: [2]float32{
+				u + float32(rand.IntN(particleSubdivision))*subU,
+				v + float32(rand.IntN(particleSubdivision))*subV,
+			},
+		})
+	}
+}
+
+// Update moves particles under gravity and removes expired ones. Particles
+// stop falling when they land on a solid block.
+func (ps *ParticleSystem) Update(dt float32) {
+	alive := ps.particles[:0]
+	for _, p := range ps.particles {
+		p.life -= dt
+		if p.life <= 0 {
+			continue
+		}
+
+		p.velocity[1] -= particleGravity * dt
+		next := p.pos.Add(p.velocity.Mul(dt))
+		bottom := next.Y() - p.size/2
+		if p.velocity.Y() < 0 && world.IsSolid(ps.world.GetBlock(
+			int(math.Floor(float64(next.X()))),
+			int(math.Floor(float64(bottom))),
+			int(math.Floor(float64(next.Z()))))) {
+			// Rest on top of the block
+			next[1] = float32(math.Floor(float64(bottom))) + 1 + p.size/2
+			p.velocity = mgl32.Vec3{}
+		}
+		p.pos = next
+		alive = append(alive, p)
+	}
+	ps.particles = alive
+}
+
+// Count is how many particles are alive
+func (ps *ParticleSystem) Count() int {
+	return len(ps.particles)
+}
+
+// Draw renders every live particle in one instanced draw call
+func (ps *ParticleSystem) Draw(cam *camera.Camera) {
+	if len(ps.particles) == 0 {
+		return
+	}
+
+	ps.instances = ps.instances[:0]
+	for _, p := range ps.particles {
+		ps.instances = append(ps.instances, p.pos[0], p.pos[1], p.pos[2], p.size, p.uv[0], p.uv[1])
+	}
+
+	gl.UseProgram(ps.program)
+	view := cam.GetViewMatrix()
+	proj := cam.GetProjectionMatrix()
+	gl.UniformMatrix4fv(gl.GetUniformLocation(ps.program, gl.Str("view\x00")), 1, false, &view[0])
+	gl.UniformMatrix4fv(gl.GetUniformLocation(ps.program, gl.Str("projection\x00")), 1, false, &proj[0])
+	gl.Uniform2f(gl.GetUniformLocation(ps.program, gl.Str("subTileSize\x00")),
+		world.TileSize/world.TextureWidth/particleSubdivision, world.TileSize/world.TextureHeight/particleSubdivision)
+	gl.Uniform3fv(gl.GetUniformLocation(ps.program, gl.Str("light\x00")), 1, &ps.Light[0])
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, ps.atlasID)
+	gl.Uniform1i(gl.GetUniformLocation(ps.program, gl.Str("texture1\x00")), 0)
+
+	gl.BindVertexArray(ps.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, ps.instVBO)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(ps.instances)*4, gl.Ptr(ps.instances))
+	gl.DrawArraysInstanced(gl.TRIANGLES, 0, 36, int32(len(ps.particles)))
+	gl.BindVertexArray(0)
+}
+
+func (ps *ParticleSystem) Cleanup() {
+	gl.DeleteVertexArrays(1, &ps.vao)
+	gl.DeleteBuffers(1, &ps.cubeVBO)
+	gl.DeleteBuffers(1, &ps.instVBO)
+	gl.DeleteProgram(ps.program)
+}
@@ -1,11 +1,15 @@
 package render
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"voxel-game/internal/camera"
+	"voxel-game/internal/errs"
 	"voxel-game/internal/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -17,11 +21,60 @@ type Renderer struct {
 	highlightShader uint32 // For block selection
 	highlightVAO    uint32
 	highlightVBO    uint32
+
+	// Debug toggle, only affects the world pass
+	CullFaces bool
+
+	HighlightMode HighlightMode
+
+	// Direction the sunlight travels, shared by shading and shadows
+	SunDirection mgl32.Vec3
+	// Diffuse sunlight scale, 1 at noon and 0 at night
+	SunStrength float32
+	// Light reaching faces the sun doesn't, multiplied into the texture
+	AmbientColor mgl32.Vec3
+
+	// Optional single-cascade shadow map around the camera
+	Shadows bool
+	shadow  *shadowMap
+
+	// Color new chunks fade in from and distant terrain fogs into,
+	// normally the sky color
+	SkyColor mgl32.Vec3
+
+	// First-person held block / hand
+	ShowViewModel bool
+	viewModel     *viewModel
+
+	// Visible chunks with translucent geometry, reused every frame
+	translucent []*world.Chunk
 }
 
+const (
+	// 12 beams * 36 vertices per beam
+	outlineVertexCount = 432
+	// Unit cube follows the beams, faces in face-index order (6 vertices each)
+	cubeFirstVertex = outlineVertexCount
+
+	// How long a newly visible chunk takes to fade in from the sky color
+	chunkFadeSeconds = 0.5
+
+	// Alpha of translucent blocks like water
+	translucentOpacity = 0.7
+
+	// Fog thickens from this fraction of the render distance out to the
+	// edge, so chunks streaming in there are hidden
+	fogStartFraction = 0.6
+)
+
+// RenderStats counts what RenderWorld actually drew this frame, after
+// frustum culling. Returned by value so reading it never allocates.
 type RenderStats struct {
+	ChunksLoaded   int // Everything in the world, drawn or not
 	ChunksRendered int
+	ChunksCulled   int // Had geometry but were outside the frustum
 	TotalVertices  int32
+	DrawCalls      int // Chunk draws, shadow pass included
 }
 
 func NewRenderer() (*Renderer, error) {
@@ -42,14 +95,36 @@ func NewRenderer() (*Renderer, error) {
 	r := &Renderer{
 		shaderProgram:   shaderProgram,
 		highlightShader: highlightShader,
+		CullFaces:       true,
+		HighlightMode:   HighlightOutline,
+		SunDirection:    mgl32.Vec3{-0.2, -1.0, -0.3},
+		SunStrength:     1.0,
+		AmbientColor:    mgl32.Vec3{0.3, 0.3, 0.3},
+		ShowViewModel:   true,
+		SkyColor:        mgl32.Vec3{0.53, 0.81, 0.92},
+		viewModel:       newViewModel(),
 	}
 	r.initHighlightMesh()
 
+	// Shadows are optional, a missing shader just leaves them off
+	if shadow, err := newShadowMap(); err != nil {
+		fmt.Println("Warning: shadows unavailable:", err)
+	} else {
+		r.shadow = shadow
+	}
+
 	return r, nil
 }
 
 func (r *Renderer) RenderWorld(w *world.World, cam *camera.Camera, atlasTextureID uint32) RenderStats {
 	var stats RenderStats
+
+	shadowsOn := r.Shadows && r.shadow != nil
+	if shadowsOn {
+		r.shadow.updateLightSpace(cam.Position, r.SunDirection)
+		stats.DrawCalls += r.shadow.render(w, cam.Position)
+	}
+
 	gl.UseProgram(r.shaderProgram)
 
 	gl.ActiveTexture(gl.TEXTURE0)
@@ -57,6 +132,8 @@ func (r *Renderer) RenderWorld(w *world.World, cam *camera.Camera, atlasTextureI
 
 	loc := gl.GetUniformLocation(r.shaderProgram, gl.Str("texture1\x00"))
 	gl.Uniform1i(loc, 0)
+	gl.Uniform2f(gl.GetUniformLocation(r.shaderProgram, gl.Str("tileSize\x00")),
+		world.TileSize/world.TextureWidth, world.TileSize/world.TextureHeight)
 
 	// Set view and projection matrices
 	view := cam.GetViewMatrix()
@@ -71,64 +148,169 @@ func (r *Renderer) RenderWorld(w *world.World, cam *camera.Camera, atlasTextureI
 	gl.UniformMatrix4fv(projLoc, 1, false, &projection[0])
 
 	// Simple directional light
-	lightDir := mgl32.Vec3{-0.2, -1.0, -0.3}
-	gl.Uniform3fv(lightLoc, 1, &lightDir[0])
+	gl.Uniform3fv(lightLoc, 1, &r.SunDirection[0])
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("sunStrength\x00")), r.SunStrength)
+	gl.Uniform3fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("ambientColor\x00")), 1, &r.AmbientColor[0])
+
+	gl.Uniform1i(gl.GetUniformLocation(r.shaderProgram, gl.Str("shadowMap\x00")), 1)
+	if shadowsOn {
+		gl.ActiveTexture(gl.TEXTURE1)
+		gl.BindTexture(gl.TEXTURE_2D, r.shadow.depthTex)
+		gl.ActiveTexture(gl.TEXTURE0)
+		gl.UniformMatrix4fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("lightSpace\x00")), 1, false, &r.shadow.lightSpace[0])
+		gl.Uniform1i(gl.GetUniformLocation(r.shaderProgram, gl.Str("useShadows\x00")), 1)
+	} else {
+		gl.Uniform1i(gl.GetUniformLocation(r.shaderProgram, gl.Str("useShadows\x00")), 0)
+	}
+
+	if !r.CullFaces {
+		gl.Disable(gl.CULL_FACE)
+		defer gl.Enable(gl.CULL_FACE)
+	}
 
-	// Render each chunk
-	for _, chunk := range w.GetChunks() {
-		if chunk.Mesh == nil || chunk.Mesh.VertexCount == 0 {
+	// Chunk meshes are in world space, one identity model for all of them
+	model := mgl32.Ident4()
+	gl.UniformMatrix4fv(modelLoc, 1, false, &model[0])
+
+	fadeLoc := gl.GetUniformLocation(r.shaderProgram, gl.Str("fade\x00"))
+	opacityLoc := gl.GetUniformLocation(r.shaderProgram, gl.Str("opacity\x00"))
+	gl.Uniform3fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("fadeColor\x00")), 1, &r.SkyColor[0])
+	gl.Uniform1f(opacityLoc, 1)
+	now := time.Now()
+
+	// Fog by horizontal distance, reaching the sky color at the last loaded chunks
+	fogEnd := float32(w.RenderDistance() * world.ChunkSize)
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("fogStart\x00")), fogEnd*fogStartFraction)
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("fogEnd\x00")), fogEnd)
+	gl.Uniform3fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("cameraPos\x00")), 1, &cam.Position[0])
+
+	// Opaque pass, collecting the chunks that also have translucent faces
+	r.translucent = r.translucent[:0]
+	chunks := w.GetChunks()
+	stats.ChunksLoaded = len(chunks)
+	for _, chunk := range chunks {
+		opaque, translucent := hasGeometry(chunk.Mesh), hasGeometry(chunk.TransparentMesh)
+		if !opaque && !translucent {
 			continue
 		}
 
 		// Frustum culling
 		if !cam.IsChunkVisible(chunk.X, chunk.Z, world.ChunkSize) {
+			stats.ChunksCulled++
 			continue
 		}
 
-		// Set model matrix
-		model := mgl32.Ident4()
-		gl.UniformMatrix4fv(modelLoc, 1, false, &model[0])
-
-		gl.BindVertexArray(chunk.Mesh.VAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, int32(chunk.Mesh.VertexCount))
-
 		stats.ChunksRendered++
-		stats.TotalVertices += int32(chunk.Mesh.VertexCount)
+		if translucent {
+			r.translucent = append(r.translucent, chunk)
+		}
+		if opaque {
+			drawChunkMesh(chunk.Mesh, fadeLoc, now)
+			stats.TotalVertices += int32(chunk.Mesh.VertexCount)
+			stats.DrawCalls++
+		}
+	}
 
+	// Translucent pass, back to front so each chunk blends over the ones
+	// behind it. Depth writes are off so water doesn't hide water further away.
+	if len(r.translucent) > 0 {
+		camX, camZ := cam.Position.X(), cam.Position.Z()
+		distance := func(c *world.Chunk) float32 {
+			dx := float32(c.X*world.ChunkSize+world.ChunkSize/2) - camX
+			dz := float32(c.Z*world.ChunkSize+world.ChunkSize/2) - camZ
+			return dx*dx + dz*dz
+		}
+		slices.SortFunc(r.translucent, func(a, b *world.Chunk) int {
+			return cmp.Compare(distance(b), distance(a))
+		})
+
+		gl.Uniform1f(opacityLoc, translucentOpacity)
+		gl.DepthMask(false)
+		for _, chunk := range r.translucent {
+			drawChunkMesh(chunk.TransparentMesh, fadeLoc, now)
+			stats.TotalVertices += int32(chunk.TransparentMesh.VertexCount)
+			stats.DrawCalls++
+		}
+		gl.DepthMask(true)
+		gl.Uniform1f(opacityLoc, 1)
 	}
+
 	gl.BindVertexArray(0)
 	return stats
 }
 
-func (r *Renderer) DrawBlockHighlight(pos mgl32.Vec3, cam *camera.Camera, color mgl32.Vec3) {
-	gl.UseProgram(r.highlightShader)
+func hasGeometry(m *world.ChunkMesh) bool {
+	return m != nil && m.VertexCount > 0
+}
 
-	model := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z()).
-		Mul4(mgl32.Scale3D(1.001, 1.001, 1.001))
+// drawChunkMesh draws one chunk mesh, fading it in from the sky color the
+// first time it is seen
+func drawChunkMesh(m *world.ChunkMesh, fadeLoc int32, now time.Time) {
+	if m.FirstDrawn.IsZero() {
+		m.FirstDrawn = now
+	}
+	fade := float32(now.Sub(m.FirstDrawn).Seconds() / chunkFadeSeconds)
+	gl.Uniform1f(fadeLoc, min(fade, 1))
+
+	gl.BindVertexArray(m.VAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(m.VertexCount))
+}
+
+func (r *Renderer) DrawBlockHighlight(pos mgl32.Vec3, face int, cam *camera.Camera, color mgl32.Vec3) {
+	gl.UseProgram(r.highlightShader)
 
 	view := cam.GetViewMatrix()
 	proj := cam.GetProjectionMatrix()
 
 	// Use cached uniforms
-	gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("model\x00")), 1, false, &model[0])
+	modelLoc := gl.GetUniformLocation(r.highlightShader, gl.Str("model\x00"))
+	alphaLoc := gl.GetUniformLocation(r.highlightShader, gl.Str("uAlpha\x00"))
 	gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("view\x00")), 1, false, &view[0])
 	gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("projection\x00")), 1, false, &proj[0])
 	gl.Uniform3fv(gl.GetUniformLocation(r.highlightShader, gl.Str("uColor\x00")), 1, &color[0])
 
-	gl.Disable(gl.DEPTH_TEST)
-	gl.DepthMask(false)
-	gl.Disable(gl.CULL_FACE)
-
 	gl.BindVertexArray(r.highlightVAO)
 
-	// 12 beams * 36 vertices per beam = 432 vertices
-	gl.DrawArrays(gl.TRIANGLES, 0, 432)
+	if r.HighlightMode&(HighlightShade|HighlightFace) != 0 {
+		// Overlays sit just outside the block and depth test against the world
+		model := mgl32.Translate3D(pos.X()+0.5, pos.Y()+0.5, pos.Z()+0.5).
+			Mul4(mgl32.Scale3D(1.002, 1.002, 1.002)).
+			Mul4(mgl32.Translate3D(-0.5, -0.5, -0.5))
+		gl.UniformMatrix4fv(modelLoc, 1, false, &model[0])
 
-	gl.BindVertexArray(0)
+		gl.DepthMask(false)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+
+		if r.HighlightMode&HighlightShade != 0 {
+			gl.Uniform1f(alphaLoc, 0.2)
+			gl.DrawArrays(gl.TRIANGLES, cubeFirstVertex, 36)
+		}
+		if r.HighlightMode&HighlightFace != 0 && face >= 0 && face < 6 {
+			gl.Uniform1f(alphaLoc, 0.35)
+			gl.DrawArrays(gl.TRIANGLES, int32(cubeFirstVertex+face*6), 6)
+		}
+
+		gl.DepthMask(true)
+	}
+
+	if r.HighlightMode&HighlightOutline != 0 {
+		model := mgl32.Translate3D(pos.X(), pos.Y(), pos.Z()).
+			Mul4(mgl32.Scale3D(1.001, 1.001, 1.001))
+		gl.UniformMatrix4fv(modelLoc, 1, false, &model[0])
+		gl.Uniform1f(alphaLoc, 1.0)
 
-	gl.DepthMask(true)
-	gl.Enable(gl.DEPTH_TEST)
-	gl.Enable(gl.CULL_FACE)
+		gl.Disable(gl.DEPTH_TEST)
+		gl.DepthMask(false)
+		gl.Disable(gl.CULL_FACE)
+
+		gl.DrawArrays(gl.TRIANGLES, 0, outlineVertexCount)
+
+		gl.DepthMask(true)
+		gl.Enable(gl.DEPTH_TEST)
+		gl.Enable(gl.CULL_FACE)
+	}
+
+	gl.BindVertexArray(0)
 }
 
 func (r *Renderer) initHighlightMesh() {
@@ -183,6 +365,28 @@ func (r *Renderer) initHighlightMesh() {
 	addBeam(0, 0, 0, thickness, thickness, 1)           // Left
 	addBeam(1-thickness, 0, 0, thickness, thickness, 1) // Right
 
+	// Unit cube for the shade/face overlays, CCW from outside like the chunk mesher
+	vertices = append(vertices,
+		// Front (+Z)
+		0, 0, 1, 1, 0, 1, 1, 1, 1,
+		0, 0, 1, 1, 1, 1, 0, 1, 1,
+		// Back (-Z)
+		1, 0, 0, 0, 0, 0, 0, 1, 0,
+		1, 0, 0, 0, 1, 0, 1, 1, 0,
+		// Right (+X)
+		1, 0, 1, 1, 0, 0, 1, 1, 0,
+		1, 0, 1, 1, 1, 0, 1, 1, 1,
+		// Left (-X)
+		0, 0, 0, 0, 0, 1, 0, 1, 1,
+		0, 0, 0, 0, 1, 1, 0, 1, 0,
+		// Top (+Y)
+		0, 1, 1, 1, 1, 1, 1, 1, 0,
+		0, 1, 1, 1, 1, 0, 0, 1, 0,
+		// Bottom (-Y)
+		0, 0, 0, 1, 0, 0, 1, 0, 1,
+		0, 0, 0, 1, 0, 1, 0, 0, 1,
+	)
+
 	gl.GenVertexArrays(1, &r.highlightVAO)
 	gl.GenBuffers(1, &r.highlightVBO)
 
@@ -212,20 +416,20 @@ func (r *Renderer) initHighlightMesh() {
 func createShaderProgram(vertexPath, fragmentPath string) (uint32, error) {
 	vertexSource, err := os.ReadFile(vertexPath)
 	if err != nil {
-		return 0, err
+		return 0, &errs.AssetLoadError{Path: vertexPath, Cause: err}
 	}
 	fragmentSource, err := os.ReadFile(fragmentPath)
 	if err != nil {
-		return 0, err
+		return 0, &errs.AssetLoadError{Path: fragmentPath, Cause: err}
 	}
 
 	vertexShader, err := compileShader(string(vertexSource)+"\x00", gl.VERTEX_SHADER)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("%s: %w", vertexPath, err)
 	}
 	fragmentShader, err := compileShader(string(fragmentSource)+"\x00", gl.FRAGMENT_SHADER)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("%s: %w", fragmentPath, err)
 	}
 
 	program := gl.CreateProgram()
@@ -240,7 +444,7 @@ func createShaderProgram(vertexPath, fragmentPath string) (uint32, error) {
 		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
-		return 0, fmt.Errorf("failed to link program: %v", log)
+		return 0, &errs.ShaderCompileError{Stage: "link", Log: log}
 	}
 
 	gl.DeleteShader(vertexShader)
@@ -266,8 +470,31 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to compile shader: %v", log)
+		return 0, &errs.ShaderCompileError{Stage: shaderStageName(shaderType), Log: log}
 	}
 
 	return shader, nil
 }
+
+func shaderStageName(shaderType uint32) string {
+	if shaderType == gl.VERTEX_SHADER {
+		return "vertex"
+	}
+	return "fragment"
+}
+
+// Cleanup frees the renderer's shaders and buffers, including the shadow map
+// and view model
+func (r *Renderer) Cleanup() {
+	gl.DeleteVertexArrays(1, &r.highlightVAO)
+	gl.DeleteBuffers(1, &r.highlightVBO)
+	if r.highlightShader != r.shaderProgram {
+		gl.DeleteProgram(r.highlightShader)
+	}
+	gl.DeleteProgram(r.shaderProgram)
+
+	if r.shadow != nil {
+		r.shadow.cleanup()
+	}
+	r.viewModel.cleanup()
+}
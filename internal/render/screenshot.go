@@ -0,0 +1,51 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// CaptureScreenshot saves the current framebuffer contents as a PNG at path,
+// creating its directory if needed. Call it after drawing and before
+// swapping buffers.
+func CaptureScreenshot(width, height int, path string) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("could not capture screenshot: empty framebuffer %dx%d", width, height)
+	}
+
+	pixels := make([]uint8, width*height*4)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
+
+	// GL rows start at the bottom, image rows at the top. Alpha is forced
+	// opaque, blending leaves it below 1 in places.
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	rowSize := width * 4
+	for y := 0; y < height; y++ {
+		src := pixels[(height-1-y)*rowSize : (height-y)*rowSize]
+		dst := img.Pix[y*img.Stride : y*img.Stride+rowSize]
+		copy(dst, src)
+		for x := 3; x < rowSize; x += 4 {
+			dst[x] = 255
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create screenshot directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create screenshot: %w", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("could not encode screenshot: %w", err)
+	}
+	return f.Close()
+}
@@ -3,7 +3,8 @@
 out vec4 FragColor;
 
 uniform vec3 uColor;
+uniform float uAlpha;
 
 void main() {
-    FragColor = vec4(uColor, 1.0);
+    FragColor = vec4(uColor, uAlpha);
 }
\ No newline at end of file
@@ -3,21 +3,105 @@
 out vec4 FragColor;
 
 in vec2 TexCoord;
+in vec2 LocalUV;
+in float AO;
+in float SkyLight;
+in vec3 BlockLight;
 in vec3 Normal;
 in vec3 FragPos;
+in vec4 FragPosLightSpace;
 
 uniform sampler2D texture1;
+uniform vec2 tileSize; // One atlas tile in UV units
+uniform sampler2D shadowMap;
 uniform vec3 lightDir;
+uniform float sunStrength;
+uniform vec3 ambientColor;
+uniform bool useShadows;
+
+// Fade-in for newly streamed chunks, 0 = all fadeColor, 1 = fully shown
+uniform float fade;
+uniform vec3 fadeColor;
+
+// 1 for the opaque pass, lower for translucent blocks
+uniform float opacity;
+
+// Distance fog into fadeColor between fogStart and fogEnd blocks from the
+// camera, measured horizontally. fogEnd 0 turns it off.
+uniform float fogStart;
+uniform float fogEnd;
+uniform vec3 cameraPos;
+
+// Fraction of the fragment hidden from the sun, 3x3 PCF
+float shadowAmount(vec3 norm, vec3 toLight) {
+    vec3 proj = FragPosLightSpace.xyz / FragPosLightSpace.w * 0.5 + 0.5;
+    if (proj.z > 1.0) {
+        return 0.0;
+    }
+
+    // Slope-scaled bias against shadow acne. The depth range spans a few
+    // hundred blocks, so 0.0001 is roughly a twentieth of a block.
+    float bias = max(0.0004 * (1.0 - dot(norm, toLight)), 0.0001);
+
+    float shadow = 0.0;
+    vec2 texel = 1.0 / textureSize(shadowMap, 0);
+    for (int x = -1; x <= 1; x++) {
+        for (int y = -1; y <= 1; y++) {
+            float closest = texture(shadowMap, proj.xy + vec2(x, y) * texel).r;
+            shadow += proj.z - bias > closest ? 1.0 : 0.0;
+        }
+    }
+    return shadow / 9.0;
+}
+
+// Fixed per-face weight for the ambient term: tops brightest, then Z sides,
+// X sides, and bottoms darkest. Keeps block edges readable when the sun is
+// overhead or down and the Lambert term alone lights every side the same.
+float ambientFaceWeight(vec3 norm) {
+    float side = mix(0.7, 0.85, abs(norm.z));
+    if (norm.y >= 0.0) {
+        return mix(side, 1.0, norm.y);
+    }
+    return mix(side, 0.55, -norm.y);
+}
+
+// How much of the fragment is hidden by fog, 0 to 1. Exponential-squared
+// falloff so nearby terrain stays clear and the edge is fully covered.
+float fogAmount() {
+    if (fogEnd <= 0.0) {
+        return 0.0;
+    }
+    float dist = length(FragPos.xz - cameraPos.xz);
+    float t = clamp((dist - fogStart) / (fogEnd - fogStart), 0.0, 1.0);
+    return 1.0 - exp(-9.0 * t * t);
+}
 
 void main() {
-    vec4 texColor = texture(texture1, TexCoord);
+    // Repeat the tile across merged quads. Gradients come from the unwrapped
+    // coordinate so mip selection doesn't jump at the tile seams.
+    vec2 unwrapped = TexCoord + LocalUV * tileSize;
+    vec2 uv = TexCoord + fract(LocalUV) * tileSize;
+    vec4 texColor = textureGrad(texture1, uv, dFdx(unwrapped), dFdy(unwrapped));
 
     vec3 norm = normalize(Normal);
     vec3 lightDirNormalized = normalize(-lightDir);
-    float diff = max(dot(norm, lightDirNormalized), 0.0);
+    float diff = max(dot(norm, lightDirNormalized), 0.0) * sunStrength;
+
+    if (useShadows && diff > 0.0) {
+        diff *= 1.0 - shadowAmount(norm, lightDirNormalized);
+    }
     
-    vec3 ambient = 0.3 * texColor.rgb;
+    vec3 ambient = ambientColor * ambientFaceWeight(norm) * texColor.rgb;
     vec3 diffuse = diff * texColor.rgb;
     
-    FragColor = vec4(ambient + diffuse, 1.0);
+    // Sky light dims both terms, so caves and overhangs stay dark even
+    // where the shadow map misses them. Block light doesn't care about the
+    // time of day.
+    vec3 glow = BlockLight * texColor.rgb;
+    vec3 lit = ((ambient + diffuse) * SkyLight + glow) * AO;
+
+    vec3 color = mix(fadeColor, lit, fade);
+    color = mix(color, fadeColor, fogAmount());
+
+    FragColor = vec4(color, opacity);
 }
@@ -0,0 +1,14 @@
+#version 410 core
+
+out vec4 FragColor;
+
+in vec2 TexCoord;
+in float Shade;
+
+uniform sampler2D texture1;
+uniform vec3 light;
+
+void main() {
+    vec4 texColor = texture(texture1, TexCoord);
+    FragColor = vec4(texColor.rgb * Shade * light, 1.0);
+}
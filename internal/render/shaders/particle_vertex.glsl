@@ -0,0 +1,24 @@
+#version 410 core
+
+// Unit cube, shared by every particle
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec2 aLocalUV;
+layout (location = 2) in float aShade;
+
+// Per particle
+layout (location = 3) in vec4 aCenterSize; // xyz center, w edge length
+layout (location = 4) in vec2 aUVOrigin;   // Corner of the atlas sub-rect
+
+out vec2 TexCoord;
+out float Shade;
+
+uniform mat4 view;
+uniform mat4 projection;
+uniform vec2 subTileSize; // Atlas UV size of the sub-rect each particle shows
+
+void main() {
+    TexCoord = aUVOrigin + aLocalUV * subTileSize;
+    Shade = aShade;
+    vec3 worldPos = aCenterSize.xyz + aPos * aCenterSize.w;
+    gl_Position = projection * view * vec4(worldPos, 1.0);
+}
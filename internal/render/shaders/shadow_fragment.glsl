@@ -0,0 +1,5 @@
+#version 410 core
+
+// Depth-only pass, the depth buffer is written automatically
+void main() {
+}
@@ -0,0 +1,28 @@
+#version 410 core
+
+out vec4 FragColor;
+
+in vec2 NDC;
+
+// Inverse of projection * view with the camera translation removed, turns a
+// screen position back into a view direction
+uniform mat4 invViewProj;
+uniform vec3 zenithColor;
+uniform vec3 horizonColor;
+uniform vec3 toSun; // Unit vector towards the sun
+uniform vec3 sunGlowColor;
+
+void main() {
+    vec4 far = invViewProj * vec4(NDC, 1.0, 1.0);
+    vec3 dir = normalize(far.xyz / far.w);
+
+    // Horizon color near and below the horizon, blending up to the zenith
+    float up = smoothstep(0.0, 0.6, max(dir.y, 0.0));
+    vec3 color = mix(horizonColor, zenithColor, up);
+
+    // Soft haze around the sun, strongest at the horizon
+    float sun = max(dot(dir, toSun), 0.0);
+    color += sunGlowColor * pow(sun, 12.0) * (1.0 - up * 0.5);
+
+    FragColor = vec4(color, 1.0);
+}
@@ -0,0 +1,10 @@
+#version 410 core
+
+// Fullscreen triangle from the vertex index, no vertex buffer needed
+out vec2 NDC;
+
+void main() {
+    vec2 pos = vec2((gl_VertexID << 1) & 2, gl_VertexID & 2) * 2.0 - 1.0;
+    NDC = pos;
+    gl_Position = vec4(pos, 0.0, 1.0);
+}
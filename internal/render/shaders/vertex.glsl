@@ -3,18 +3,33 @@
 layout (location = 0) in vec3 aPos;
 layout (location = 1) in vec2 aTexCoord;
 layout (location = 2) in vec3 aNormal;
+layout (location = 3) in vec2 aLocalUV;
+layout (location = 4) in float aAO;
+layout (location = 5) in float aSkyLight;
+layout (location = 6) in vec3 aBlockLight;
 
-out vec2 TexCoord;
+out vec2 TexCoord; // Atlas tile origin
+out vec2 LocalUV;  // Position within the face in tiles, repeats per block
+out float AO;      // Ambient occlusion, 1 = unoccluded
+out float SkyLight; // Sky light brightness, 1 = open sky
+out vec3 BlockLight; // Light from emitting blocks, added on top
 out vec3 Normal;
 out vec3 FragPos;
+out vec4 FragPosLightSpace;
 
 uniform mat4 model;
 uniform mat4 view;
 uniform mat4 projection;
+uniform mat4 lightSpace;
 
 void main() {
     TexCoord = aTexCoord; // Pass it through
+    LocalUV = aLocalUV;
+    AO = aAO;
+    SkyLight = aSkyLight;
+    BlockLight = aBlockLight;
     Normal = mat3(transpose(inverse(model))) * aNormal;
     FragPos = vec3(model * vec4(aPos, 1.0));
+    FragPosLightSpace = lightSpace * vec4(FragPos, 1.0);
     gl_Position = projection * view * vec4(FragPos, 1.0);
 }
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"fmt"
+	"math"
+
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	shadowMapSize = 2048
+	// Half-width in blocks of the single shadow cascade around the player
+	shadowRadius = 64.0
+	// Half-depth in blocks of the light's view volume, covers the full world height
+	shadowDepth = 256.0
+)
+
+// shadowMap is a depth texture rendered from the sun's direction
+type shadowMap struct {
+	fbo      uint32
+	depthTex uint32
+	program  uint32
+
+	lightSpace mgl32.Mat4
+}
+
+func newShadowMap() (*shadowMap, error) {
+	program, err := createShaderProgram("internal/render/shaders/shadow_vertex.glsl", "internal/render/shaders/shadow_fragment.glsl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shadow shader: %w", err)
+	}
+
+	s := &shadowMap{program: program}
+
+	gl.GenTextures(1, &s.depthTex)
+	gl.BindTexture(gl.TEXTURE_2D, s.depthTex)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT24, shadowMapSize, shadowMapSize, 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+	// Anything outside the cascade reads as fully lit
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_BORDER)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_BORDER)
+	border := []float32{1, 1, 1, 1}
+	gl.TexParameterfv(gl.TEXTURE_2D, gl.TEXTURE_BORDER_COLOR, &border[0])
+
+	gl.GenFramebuffers(1, &s.fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.fbo)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, s.depthTex, 0)
+	gl.DrawBuffer(gl.NONE)
+	gl.ReadBuffer(gl.NONE)
+
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		s.cleanup()
+		return nil, fmt.Errorf("shadow framebuffer incomplete: 0x%x", status)
+	}
+
+	return s, nil
+}
+
+// updateLightSpace fits the cascade around center, looking along sunDir
+func (s *shadowMap) updateLightSpace(center, sunDir mgl32.Vec3) {
+	dir := sunDir.Normalize()
+	up := mgl32.Vec3{0, 1, 0}
+	if math.Abs(float64(dir.Y())) > 0.99 {
+		up = mgl32.Vec3{0, 0, 1}
+	}
+	lightView := mgl32.LookAtV(mgl32.Vec3{}, dir, up)
+
+	// Snap the cascade center to whole texels in light space so shadow
+	// edges don't crawl as the player moves
+	c := lightView.Mul4x1(center.Vec4(1))
+	texel := float32(2 * shadowRadius / shadowMapSize)
+	cx := float32(math.Floor(float64(c.X()/texel))) * texel
+	cy := float32(math.Floor(float64(c.Y()/texel))) * texel
+
+	// The light looks down -Z, so the center sits at distance -c.Z()
+	proj := mgl32.Ortho(
+		cx-shadowRadius, cx+shadowRadius,
+		cy-shadowRadius, cy+shadowRadius,
+		-c.Z()-shadowDepth, -c.Z()+shadowDepth,
+	)
+	s.lightSpace = proj.Mul4(lightView)
+}
+
+// render draws every chunk near center into the depth texture. Returns the
+// number of draw calls.
+func (s *shadowMap) render(w *world.World, center mgl32.Vec3) int {
+	var viewport [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &viewport[0])
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, s.fbo)
+	gl.Viewport(0, 0, shadowMapSize, shadowMapSize)
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	// Terrain meshes are single-sided, so draw both sides to catch every occluder
+	gl.Disable(gl.CULL_FACE)
+
+	gl.UseProgram(s.program)
+	model := mgl32.Ident4()
+	gl.UniformMatrix4fv(gl.GetUniformLocation(s.program, gl.Str("model\x00")), 1, false, &model[0])
+	gl.UniformMatrix4fv(gl.GetUniformLocation(s.program, gl.Str("lightSpace\x00")), 1, false, &s.lightSpace[0])
+
+	// Chunks past this distance can't land inside the cascade
+	reach := float32(shadowRadius + world.ChunkSize)
+	drawCalls := 0
+	for _, chunk := range w.GetChunks() {
+		if chunk.Mesh == nil || chunk.Mesh.VertexCount == 0 {
+			continue
+		}
+		cx := float32(chunk.X*world.ChunkSize+world.ChunkSize/2) - center.X()
+		cz := float32(chunk.Z*world.ChunkSize+world.ChunkSize/2) - center.Z()
+		if cx*cx+cz*cz > reach*reach {
+			continue
+		}
+
+		gl.BindVertexArray(chunk.Mesh.VAO)
+		gl.DrawArrays(gl.TRIANGLES, 0, int32(chunk.Mesh.VertexCount))
+		drawCalls++
+	}
+	gl.BindVertexArray(0)
+
+	gl.Enable(gl.CULL_FACE)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Viewport(viewport[0], viewport[1], viewport[2], viewport[3])
+	return drawCalls
+}
+
+func (s *shadowMap) cleanup() {
+	gl.DeleteFramebuffers(1, &s.fbo)
+	gl.DeleteTextures(1, &s.depthTex)
+	gl.DeleteProgram(s.program)
+}
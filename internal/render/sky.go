@@ -0,0 +1,64 @@
+package render
+
+import (
+	"fmt"
+
+	"voxel-game/internal/camera"
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// SkyRenderer draws a vertical gradient sky behind the world, driven by the
+// day clock's colors and sun position
+type SkyRenderer struct {
+	program uint32
+	vao     uint32 // Empty, core profile needs one bound to draw
+}
+
+func NewSkyRenderer() (*SkyRenderer, error) {
+	program, err := createShaderProgram("internal/render/shaders/sky_vertex.glsl", "internal/render/shaders/sky_fragment.glsl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sky shader: %w", err)
+	}
+
+	s := &SkyRenderer{program: program}
+	gl.GenVertexArrays(1, &s.vao)
+	return s, nil
+}
+
+// DrawSky fills the screen with the sky for the clock's time of day. Call
+// it after clearing and before RenderWorld; it writes no depth.
+func (s *SkyRenderer) DrawSky(cam *camera.Camera, clock *world.DayClock) {
+	// Only the camera's rotation matters, the sky is infinitely far away
+	view := cam.GetViewMatrix()
+	view.SetCol(3, mgl32.Vec4{0, 0, 0, 1})
+	invViewProj := cam.GetProjectionMatrix().Mul4(view).Inv()
+
+	zenith, horizon := clock.SkyGradient()
+	toSun := clock.SunDirection().Mul(-1)
+	glow := clock.SunGlowColor()
+
+	gl.UseProgram(s.program)
+	gl.UniformMatrix4fv(gl.GetUniformLocation(s.program, gl.Str("invViewProj\x00")), 1, false, &invViewProj[0])
+	gl.Uniform3fv(gl.GetUniformLocation(s.program, gl.Str("zenithColor\x00")), 1, &zenith[0])
+	gl.Uniform3fv(gl.GetUniformLocation(s.program, gl.Str("horizonColor\x00")), 1, &horizon[0])
+	gl.Uniform3fv(gl.GetUniformLocation(s.program, gl.Str("toSun\x00")), 1, &toSun[0])
+	gl.Uniform3fv(gl.GetUniformLocation(s.program, gl.Str("sunGlowColor\x00")), 1, &glow[0])
+
+	gl.Disable(gl.DEPTH_TEST)
+	gl.DepthMask(false)
+
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, 3)
+	gl.BindVertexArray(0)
+
+	gl.DepthMask(true)
+	gl.Enable(gl.DEPTH_TEST)
+}
+
+func (s *SkyRenderer) Cleanup() {
+	gl.DeleteVertexArrays(1, &s.vao)
+	gl.DeleteProgram(s.program)
+}
@@ -7,6 +7,8 @@ import (
 	_ "image/png" // Import PNG decoder
 	"os"
 
+	"voxel-game/internal/errs"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 )
 
@@ -17,13 +19,13 @@ type Texture struct {
 func LoadTexture(path string) (*Texture, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open texture file: %w", err)
+		return nil, &errs.AssetLoadError{Path: path, Cause: err}
 	}
 	defer file.Close()
 
 	img, _, err := image.Decode(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode texture: %w", err)
+		return nil, &errs.AssetLoadError{Path: path, Cause: fmt.Errorf("failed to decode texture: %w", err)}
 	}
 
 	rgba := image.NewRGBA(img.Bounds())
@@ -57,3 +59,9 @@ func LoadTexture(path string) (*Texture, error) {
 
 	return &Texture{ID: texture}, nil
 }
+
+// Delete frees the texture on the GPU
+func (t *Texture) Delete() {
+	gl.DeleteTextures(1, &t.ID)
+	t.ID = 0
+}
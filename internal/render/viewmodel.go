@@ -0,0 +1,144 @@
+package render
+
+import (
+	"math"
+
+	"voxel-game/internal/camera"
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ViewModelState is what the first-person view model needs each frame
+type ViewModelState struct {
+	Block     world.BlockType // BlockAir shows the empty hand
+	WalkPhase float32         // Player view-bob phase
+	Swing     float32         // 0 to 1 through a break/place swing
+}
+
+// viewModel holds the held-block mesh, rebuilt only when the block changes.
+// The empty hand reuses the highlight unit cube.
+type viewModel struct {
+	vao uint32
+	vbo uint32
+
+	block       world.BlockType
+	vertexCount int32
+}
+
+var skinColor = mgl32.Vec3{0.87, 0.68, 0.55}
+
+func newViewModel() *viewModel {
+	vm := &viewModel{block: world.BlockAir}
+	gl.GenVertexArrays(1, &vm.vao)
+	gl.GenBuffers(1, &vm.vbo)
+	return vm
+}
+
+func (vm *viewModel) setBlock(blockType world.BlockType) {
+	if blockType == vm.block {
+		return
+	}
+	vm.block = blockType
+
+	vertices := world.AppendBlockCube(make([]float32, 0, 36*world.VertexFloats), 0, 0, 0, world.Block{Type: blockType})
+	vm.vertexCount = int32(len(vertices) / world.VertexFloats)
+
+	gl.BindVertexArray(vm.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vm.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+
+	// Same layout as chunk meshes: position, tile origin, normal, local UV, AO, sky and block light
+	stride := int32(world.VertexFloats * 4)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, gl.PtrOffset(3*4))
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribPointer(3, 2, gl.FLOAT, false, stride, gl.PtrOffset(8*4))
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointer(4, 1, gl.FLOAT, false, stride, gl.PtrOffset(10*4))
+	gl.EnableVertexAttribArray(5)
+	gl.VertexAttribPointer(5, 1, gl.FLOAT, false, stride, gl.PtrOffset(11*4))
+	gl.EnableVertexAttribArray(6)
+	gl.VertexAttribPointer(6, 3, gl.FLOAT, false, stride, gl.PtrOffset(12*4))
+
+	gl.BindVertexArray(0)
+}
+
+// DrawViewModel draws the held block, or the bare arm when nothing is held,
+// in the lower right of the screen. It clears depth first so it never clips
+// into nearby terrain.
+func (r *Renderer) DrawViewModel(state ViewModelState, cam *camera.Camera, atlasTextureID uint32) {
+	if !r.ShowViewModel {
+		return
+	}
+
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	// View space: camera at the origin looking down -Z
+	view := mgl32.Ident4()
+	proj := cam.GetProjectionMatrix()
+
+	// Bob and swing on top of the camera's own bob
+	bobX := float32(math.Sin(float64(state.WalkPhase/2))) * 0.02
+	bobY := float32(math.Abs(math.Sin(float64(state.WalkPhase)))) * 0.02
+	swing := float32(math.Sin(float64(state.Swing) * math.Pi))
+	swingRot := mgl32.HomogRotate3DX(mgl32.DegToRad(-40 * swing))
+
+	if state.Block == world.BlockAir {
+		model := mgl32.Translate3D(0.45+bobX, -0.45+bobY-0.1*swing, -0.6).
+			Mul4(swingRot).
+			Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(-10))).
+			Mul4(mgl32.Scale3D(0.15, 0.15, 0.6)).
+			Mul4(mgl32.Translate3D(-0.5, -0.5, -0.5))
+
+		gl.UseProgram(r.highlightShader)
+		gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("model\x00")), 1, false, &model[0])
+		gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("view\x00")), 1, false, &view[0])
+		gl.UniformMatrix4fv(gl.GetUniformLocation(r.highlightShader, gl.Str("projection\x00")), 1, false, &proj[0])
+		gl.Uniform3fv(gl.GetUniformLocation(r.highlightShader, gl.Str("uColor\x00")), 1, &skinColor[0])
+		gl.Uniform1f(gl.GetUniformLocation(r.highlightShader, gl.Str("uAlpha\x00")), 1.0)
+
+		gl.BindVertexArray(r.highlightVAO)
+		gl.DrawArrays(gl.TRIANGLES, cubeFirstVertex, 36)
+		gl.BindVertexArray(0)
+		return
+	}
+
+	r.viewModel.setBlock(state.Block)
+
+	model := mgl32.Translate3D(0.55+bobX, -0.5+bobY-0.15*swing, -0.9).
+		Mul4(swingRot).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(45))).
+		Mul4(mgl32.Scale3D(0.35, 0.35, 0.35)).
+		Mul4(mgl32.Translate3D(-0.5, -0.5, -0.5))
+
+	// Fixed view-space light so the block reads the same whichever way we face
+	lightDir := mgl32.Vec3{-0.4, -1.0, -0.6}
+
+	gl.UseProgram(r.shaderProgram)
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, atlasTextureID)
+	gl.Uniform1i(gl.GetUniformLocation(r.shaderProgram, gl.Str("texture1\x00")), 0)
+	gl.UniformMatrix4fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("model\x00")), 1, false, &model[0])
+	gl.UniformMatrix4fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("view\x00")), 1, false, &view[0])
+	gl.UniformMatrix4fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("projection\x00")), 1, false, &proj[0])
+	gl.Uniform3fv(gl.GetUniformLocation(r.shaderProgram, gl.Str("lightDir\x00")), 1, &lightDir[0])
+	gl.Uniform1i(gl.GetUniformLocation(r.shaderProgram, gl.Str("useShadows\x00")), 0)
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("fade\x00")), 1.0)
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("opacity\x00")), 1.0)
+	gl.Uniform1f(gl.GetUniformLocation(r.shaderProgram, gl.Str("fogEnd\x00")), 0) // No fog in view space
+
+	gl.BindVertexArray(r.viewModel.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, r.viewModel.vertexCount)
+	gl.BindVertexArray(0)
+}
+
+func (vm *viewModel) cleanup() {
+	gl.DeleteVertexArrays(1, &vm.vao)
+	gl.DeleteBuffers(1, &vm.vbo)
+}
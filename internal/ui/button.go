@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Button is a labeled rectangle that lights up under the cursor and runs
+// OnClick when clicked. A click is a press and release both on the button,
+// so sliding off before letting go cancels it. The owner places it with
+// SetBounds, in UI coordinates.
+type Button struct {
+	label *Text
+	font  *Font
+
+	x, y          float32
+	width, height float32
+
+	hovered bool
+	pressed bool
+
+	OnClick func()
+
+	vao         uint32
+	vbo         uint32
+	vertexCount int
+	needsUpdate bool
+
+	texture uint32
+}
+
+// Button colors: the border, then the face at rest, hovered and held down
+var (
+	buttonBorder      = mgl32.Vec3{0.8, 0.8, 0.8}
+	buttonBorderHover = mgl32.Vec3{1.0, 1.0, 1.0}
+	buttonFace        = mgl32.Vec3{0.25, 0.25, 0.25}
+	buttonFaceHover   = mgl32.Vec3{0.4, 0.4, 0.45}
+	buttonFacePressed = mgl32.Vec3{0.15, 0.15, 0.15}
+)
+
+func NewButton(font *Font, label string, x, y, width, height float32, onClick func()) *Button {
+	text := NewText(font, label, 0, 0, 1.0, mgl32.Vec3{1, 1, 1})
+	text.SetAlignment(AlignCenter)
+	return &Button{
+		label:       text,
+		font:        font,
+		x:           x,
+		y:           y,
+		width:       width,
+		height:      height,
+		OnClick:     onClick,
+		needsUpdate: true,
+	}
+}
+
+func (b *Button) Init() error {
+	gl.GenVertexArrays(1, &b.vao)
+	gl.GenBuffers(1, &b.vbo)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &b.texture)
+	gl.BindTexture(gl.TEXTURE_2D, b.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	b.label.Init()
+	b.generateGeometry()
+
+	checkGLError("Button.Init")
+	return nil
+}
+
+func (b *Button) SetLabel(label string) {
+	if b.label.content != label {
+		b.label.content = label
+		b.needsUpdate = true
+	}
+}
+
+// SetBounds moves and resizes the button
+func (b *Button) SetBounds(x, y, width, height float32) {
+	if b.x != x || b.y != y || b.width != width || b.height != height {
+		b.x, b.y, b.width, b.height = x, y, width, height
+		b.needsUpdate = true
+	}
+}
+
+// Contains reports whether x, y is over the button
+func (b *Button) Contains(x, y float32) bool {
+	return x >= b.x && x < b.x+b.width && y >= b.y && y < b.y+b.height
+}
+
+// Reset drops hover and press, e.g. when the button is hidden with the
+// cursor still over it
+func (b *Button) Reset() {
+	if b.hovered || b.pressed {
+		b.hovered, b.pressed = false, false
+		b.needsUpdate = true
+	}
+}
+
+func (b *Button) HandleCursor(x, y float32) {
+	if hovered := b.Contains(x, y); hovered != b.hovered {
+		b.hovered = hovered
+		b.needsUpdate = true
+	}
+}
+
+func (b *Button) HandleClick(x, y float32, pressed bool) bool {
+	over := b.Contains(x, y)
+	if pressed {
+		if !over {
+			return false
+		}
+		b.pressed = true
+		b.needsUpdate = true
+		return true
+	}
+
+	if !b.pressed {
+		return false
+	}
+	b.pressed = false
+	b.needsUpdate = true
+	if over && b.OnClick != nil {
+		b.OnClick()
+	}
+	return true
+}
+
+func (b *Button) Update(state interface{}) {
+	if b.needsUpdate {
+		b.generateGeometry()
+	}
+}
+
+func (b *Button) generateGeometry() {
+	border, face := buttonBorder, buttonFace
+	if b.hovered {
+		border, face = buttonBorderHover, buttonFaceHover
+	}
+	if b.pressed {
+		face = buttonFacePressed
+	}
+
+	// Border, then the face inset by 2 pixels
+	vertices := make([]float32, 0, 2*6*7)
+	vertices = append(vertices, createFilledRect(b.x, b.y, b.width, b.height, border)...)
+	vertices = append(vertices, createFilledRect(b.x+2, b.y+2, b.width-4, b.height-4, face)...)
+
+	b.vertexCount = len(vertices) / 7
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(b.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	// Label centered, capitals centered vertically. Held down it sinks a
+	// pixel to feel pushed in.
+	sink := float32(0)
+	if b.pressed {
+		sink = 1
+	}
+	b.label.x = b.x + b.width/2 + sink
+	b.label.y = b.y + (b.height+b.font.Ascent*b.label.scale)/2 + sink
+	b.label.generateGeometry()
+
+	b.needsUpdate = false
+	checkGLError("Button.generateGeometry")
+}
+
+func (b *Button) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	// Hover and press change between Update and Draw
+	if b.needsUpdate {
+		b.generateGeometry()
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, b.texture)
+
+	gl.BindVertexArray(b.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(b.vertexCount))
+	gl.BindVertexArray(0)
+
+	b.label.Draw(shaderProgram, projection)
+
+	checkGLError("Button.Draw")
+}
+
+func (b *Button) Cleanup() {
+	gl.DeleteVertexArrays(1, &b.vao)
+	gl.DeleteBuffers(1, &b.vbo)
+	gl.DeleteTextures(1, &b.texture)
+	b.label.Cleanup()
+}
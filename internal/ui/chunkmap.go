@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ChunkMap is a debug-only top-down grid of chunk pipeline states, drawn in
+// the top-right corner. North (-Z) is up.
+type ChunkMap struct {
+	vao uint32
+	vbo uint32
+
+	screenWidth  int
+	screenHeight int
+	visible      bool
+
+	cellSize float32
+	margin   float32
+
+	vertices    []float32
+	vertexCount int
+
+	texture uint32
+}
+
+func NewChunkMap(screenWidth, screenHeight int) *ChunkMap {
+	return &ChunkMap{
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		cellSize:     4.0,
+		margin:       10.0,
+	}
+}
+
+func (m *ChunkMap) Init() error {
+	gl.GenVertexArrays(1, &m.vao)
+	gl.GenBuffers(1, &m.vbo)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &m.texture)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	checkGLError("ChunkMap.Init")
+	return nil
+}
+
+func (m *ChunkMap) SetVisible(visible bool) {
+	m.visible = visible
+}
+
+func (m *ChunkMap) Update(state interface{}) {
+	if screenSize, ok := state.(*ScreenSize); ok {
+		m.screenWidth = screenSize.Width
+		m.screenHeight = screenSize.Height
+		return
+	}
+
+	mapState, ok := state.(ChunkMapState)
+	if !ok || !m.visible {
+		return
+	}
+	m.generateGeometry(mapState)
+}
+
+func (m *ChunkMap) generateGeometry(state ChunkMapState) {
+	side := state.Radius*2 + 1
+	if len(state.Cells) < side*side {
+		return
+	}
+
+	size := float32(side) * m.cellSize
+	startX := float32(m.screenWidth) - m.margin - size
+	startY := m.margin
+
+	m.vertices = m.vertices[:0]
+	m.vertices = append(m.vertices, createFilledRect(startX-2, startY-2, size+4, size+4, mgl32.Vec3{0.05, 0.05, 0.05})...)
+
+	for row := 0; row < side; row++ {
+		for col := 0; col < side; col++ {
+			status := state.Cells[row*side+col]
+			if status == world.ChunkMissing {
+				continue
+			}
+			x := startX + float32(col)*m.cellSize
+			y := startY + float32(row)*m.cellSize
+			// Leave a one pixel gap so individual cells stay readable
+			m.vertices = append(m.vertices, createFilledRect(x, y, m.cellSize-1, m.cellSize-1, chunkStatusColor(status))...)
+		}
+	}
+
+	// Mark the player's chunk
+	center := startX + float32(state.Radius)*m.cellSize
+	centerY := startY + float32(state.Radius)*m.cellSize
+	m.vertices = append(m.vertices, createFilledRect(center, centerY, m.cellSize-1, m.cellSize-1, mgl32.Vec3{1, 1, 1})...)
+
+	m.vertexCount = len(m.vertices) / 7
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(m.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(m.vertices)*4, gl.Ptr(m.vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	checkGLError("ChunkMap.generateGeometry")
+}
+
+func (m *ChunkMap) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	if !m.visible || m.vertexCount == 0 {
+		return
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+
+	gl.BindVertexArray(m.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(m.vertexCount))
+	gl.BindVertexArray(0)
+
+	checkGLError("ChunkMap.Draw")
+}
+
+func (m *ChunkMap) Cleanup() {
+	gl.DeleteVertexArrays(1, &m.vao)
+	gl.DeleteBuffers(1, &m.vbo)
+	gl.DeleteTextures(1, &m.texture)
+}
+
+func chunkStatusColor(status world.ChunkStatus) mgl32.Vec3 {
+	switch status {
+	case world.ChunkQueued:
+		return mgl32.Vec3{0.2, 0.4, 0.9} // Blue: queued for generation
+	case world.ChunkGenerated:
+		return mgl32.Vec3{0.9, 0.6, 0.1} // Orange: waiting for a mesh
+	case world.ChunkMeshed:
+		return mgl32.Vec3{0.2, 0.8, 0.2}
+	case world.ChunkEmpty:
+		return mgl32.Vec3{0.3, 0.3, 0.4}
+	case world.ChunkDirty:
+		return mgl32.Vec3{0.9, 0.2, 0.7} // Magenta: edited, not saved yet
+	default:
+		return mgl32.Vec3{0, 0, 0}
+	}
+}
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// consoleLine is one line of console scrollback
+type consoleLine struct {
+	text  string
+	color mgl32.Vec3
+}
+
+// Console is a drop-down panel across the top of the screen with a
+// scrollback of output over a line of typed input. It only shows text, the
+// game feeds it keys and runs what Submit returns.
+type Console struct {
+	font *Font
+
+	screenWidth  int
+	screenHeight int
+	visible      bool
+
+	lines  []consoleLine // Oldest first, at most consoleScrollback
+	scroll int           // Lines scrolled back from the newest
+
+	input   string
+	history []string
+	// Position in history while browsing with Up and Down, len(history)
+	// on the line being typed
+	historyPos int
+
+	rows   []*Text // Scrollback rows on screen, top to bottom
+	prompt *Text
+
+	vao         uint32
+	vbo         uint32
+	vertexCount int
+	needsUpdate bool
+
+	texture uint32
+}
+
+const (
+	consoleRows       = 10
+	consoleScrollback = 200
+	consoleTextScale  = 0.75
+	consolePadding    = 8
+)
+
+// Console colors: the shade multiplied into the world behind the panel, the
+// input separator, and output and error text
+var (
+	consoleDim       = mgl32.Vec3{0.25, 0.25, 0.25}
+	consoleSeparator = mgl32.Vec3{0.5, 0.5, 0.5}
+	ConsoleTextColor = mgl32.Vec3{0.9, 0.9, 0.9}
+	ConsoleEchoColor = mgl32.Vec3{0.6, 0.6, 0.6}
+)
+
+func NewConsole(font *Font, screenWidth, screenHeight int) *Console {
+	c := &Console{
+		font:         font,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		prompt:       NewText(font, "", 0, 0, consoleTextScale, ConsoleTextColor),
+		needsUpdate:  true,
+	}
+	for i := 0; i < consoleRows; i++ {
+		c.rows = append(c.rows, NewText(font, "", 0, 0, consoleTextScale, ConsoleTextColor))
+	}
+	return c
+}
+
+func (c *Console) Init() error {
+	gl.GenVertexArrays(1, &c.vao)
+	gl.GenBuffers(1, &c.vbo)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &c.texture)
+	gl.BindTexture(gl.TEXTURE_2D, c.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	for _, row := range c.rows {
+		row.Init()
+	}
+	c.prompt.Init()
+
+	checkGLError("Console.Init")
+	return nil
+}
+
+func (c *Console) SetVisible(visible bool) {
+	if visible != c.visible {
+		c.visible = visible
+		c.scroll = 0
+		c.needsUpdate = true
+	}
+}
+
+func (c *Console) IsVisible() bool {
+	return c.visible
+}
+
+// Print adds a line of output to the scrollback
+func (c *Console) Print(text string) {
+	c.PrintColor(text, ConsoleTextColor)
+}
+
+// PrintError adds a line to the scrollback in the warning color
+func (c *Console) PrintError(text string) {
+	c.PrintColor(text, WarningNotificationColor)
+}
+
+// PrintColor adds a line to the scrollback in any color
+func (c *Console) PrintColor(text string, color mgl32.Vec3) {
+	c.lines = append(c.lines, consoleLine{text: text, color: color})
+	if len(c.lines) > consoleScrollback {
+		c.lines = c.lines[len(c.lines)-consoleScrollback:]
+	}
+	// Keep the same lines on screen while scrolled back
+	if c.scroll > 0 {
+		c.scroll = min(c.scroll+1, c.maxScroll())
+	}
+	c.needsUpdate = true
+}
+
+// AddChar types a character at the end of the input line. Control
+// characters are ignored.
+func (c *Console) AddChar(ch rune) {
+	if ch < ' ' || ch == 0x7F {
+		return
+	}
+	c.input += string(ch)
+	c.needsUpdate = true
+}
+
+// Backspace deletes the last character of the input line
+func (c *Console) Backspace() {
+	if runes := []rune(c.input); len(runes) > 0 {
+		c.input = string(runes[:len(runes)-1])
+		c.needsUpdate = true
+	}
+}
+
+// Submit clears the input line and returns it, echoed to the scrollback and
+// remembered in the history. Blank lines return "".
+func (c *Console) Submit() string {
+	line := c.input
+	c.input = ""
+	c.scroll = 0
+	c.needsUpdate = true
+	if line == "" {
+		c.historyPos = len(c.history)
+		return ""
+	}
+
+	c.PrintColor("> "+line, ConsoleEchoColor)
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+	}
+	c.historyPos = len(c.history)
+	return line
+}
+
+// HistoryPrev brings back the previous line entered
+func (c *Console) HistoryPrev() {
+	if c.historyPos > 0 {
+		c.historyPos--
+		c.input = c.history[c.historyPos]
+		c.needsUpdate = true
+	}
+}
+
+// HistoryNext steps forward through the history, ending on an empty line
+func (c *Console) HistoryNext() {
+	if c.historyPos >= len(c.history) {
+		return
+	}
+	c.historyPos++
+	c.input = ""
+	if c.historyPos < len(c.history) {
+		c.input = c.history[c.historyPos]
+	}
+	c.needsUpdate = true
+}
+
+// Scroll moves the scrollback by a page, up for positive pages
+func (c *Console) Scroll(pages int) {
+	scroll := max(0, min(c.scroll+pages*(consoleRows-1), c.maxScroll()))
+	if scroll != c.scroll {
+		c.scroll = scroll
+		c.needsUpdate = true
+	}
+}
+
+// maxScroll is how far back the scrollback goes with the oldest line on
+// the top row
+func (c *Console) maxScroll() int {
+	return max(0, len(c.lines)-consoleRows)
+}
+
+func (c *Console) Update(state interface{}) {
+	if screenSize, ok := state.(*ScreenSize); ok {
+		c.screenWidth = screenSize.Width
+		c.screenHeight = screenSize.Height
+		c.needsUpdate = true
+		return
+	}
+	if c.needsUpdate && c.visible {
+		c.generateGeometry()
+	}
+}
+
+func (c *Console) generateGeometry() {
+	lineHeight := c.font.LineHeight * consoleTextScale
+	ascent := c.font.Ascent * consoleTextScale
+	logHeight := consolePadding + float32(consoleRows)*lineHeight
+	panelHeight := logHeight + lineHeight + 2*consolePadding
+
+	// Panel first, it is drawn on its own with multiply blending
+	vertices := make([]float32, 0, 2*6*7)
+	vertices = append(vertices, createFilledRect(0, 0, float32(c.screenWidth), panelHeight, consoleDim)...)
+	vertices = append(vertices, createFilledRect(0, logHeight+consolePadding/2, float32(c.screenWidth), 1, consoleSeparator)...)
+
+	// Newest line on the bottom row, scrolled back by scroll lines
+	last := len(c.lines) - c.scroll
+	for i, row := range c.rows {
+		row.content = ""
+		if index := last - consoleRows + i; index >= 0 && index < len(c.lines) {
+			row.content = c.lines[index].text
+			row.color = c.lines[index].color
+		}
+		row.x = consolePadding
+		row.y = consolePadding + ascent + float32(i)*lineHeight
+		row.generateGeometry()
+	}
+
+	c.prompt.content = "> " + c.input + "_"
+	c.prompt.x = consolePadding
+	c.prompt.y = logHeight + consolePadding + ascent
+	c.prompt.generateGeometry()
+
+	c.vertexCount = len(vertices) / 7
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(c.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, c.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	c.needsUpdate = false
+	checkGLError("Console.generateGeometry")
+}
+
+func (c *Console) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	if !c.visible {
+		return
+	}
+	// Typed into or opened since the last Update
+	if c.needsUpdate {
+		c.generateGeometry()
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, c.texture)
+	gl.BindVertexArray(c.vao)
+
+	// Dim the world behind the panel like the menu does, then the separator
+	gl.BlendFunc(gl.ZERO, gl.SRC_COLOR)
+	gl.DrawArrays(gl.TRIANGLES, 0, 6)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.DrawArrays(gl.TRIANGLES, 6, int32(c.vertexCount-6))
+	gl.BindVertexArray(0)
+
+	for _, row := range c.rows {
+		row.Draw(shaderProgram, projection)
+	}
+	c.prompt.Draw(shaderProgram, projection)
+
+	checkGLError("Console.Draw")
+}
+
+func (c *Console) Cleanup() {
+	gl.DeleteVertexArrays(1, &c.vao)
+	gl.DeleteBuffers(1, &c.vbo)
+	gl.DeleteTextures(1, &c.texture)
+	for _, row := range c.rows {
+		row.Cleanup()
+	}
+	c.prompt.Cleanup()
+}
@@ -18,20 +18,23 @@ type Crosshair struct {
 	vertexCount int
 
 	texture uint32
+
+	// Temporary color override, e.g. red when a placement is denied
+	baseColor mgl32.Vec3
+	flashTime float32
 }
 
+// NewCrosshair sizes the crosshair in UI units. The UI is laid out at a
+// fixed logical height whatever the window size, so it keeps its proportions
+// at any resolution. GL resources are created by Init, via AddElement.
 func NewCrosshair(screenWidth, screenHeight int) (*Crosshair, error) {
-	c := &Crosshair{
-		color:        mgl32.Vec3{1.0, 1.0, 0.0}, // White
+	return &Crosshair{
+		color:        mgl32.Vec3{1.0, 1.0, 0.0}, // Yellow
 		size:         10.0,
 		thickness:    2.0,
 		screenWidth:  screenWidth,
 		screenHeight: screenHeight,
-	}
-	if err := c.Init(); err != nil {
-		return nil, err
-	}
-	return c, nil
+	}, nil
 }
 
 func (c *Crosshair) Init() error {
@@ -54,9 +57,10 @@ func (c *Crosshair) Init() error {
 }
 
 func (c *Crosshair) generateGeometry() {
-	// Calculate center of screen
-	centerX := float32(c.screenWidth) / 2.0
-	centerY := float32(c.screenHeight) / 2.0
+	// Center of the screen, on a whole unit so an odd width doesn't leave
+	// the lines straddling pixels
+	centerX := float32(c.screenWidth / 2)
+	centerY := float32(c.screenHeight / 2)
 
 	vertices := make([]float32, 0)
 
@@ -125,6 +129,7 @@ func (c *Crosshair) Draw(shaderProgram uint32, projection mgl32.Mat4) {
 func (c *Crosshair) Cleanup() {
 	gl.DeleteVertexArrays(1, &c.vao)
 	gl.DeleteBuffers(1, &c.vbo)
+	gl.DeleteTextures(1, &c.texture)
 }
 
 func (c *Crosshair) SetColor(color mgl32.Vec3) {
@@ -132,6 +137,26 @@ func (c *Crosshair) SetColor(color mgl32.Vec3) {
 	c.generateGeometry()
 }
 
+// Flash tints the crosshair for duration seconds, then restores its color
+func (c *Crosshair) Flash(color mgl32.Vec3, duration float32) {
+	if c.flashTime <= 0 {
+		c.baseColor = c.color
+	}
+	c.flashTime = duration
+	c.SetColor(color)
+}
+
+// Tick counts down an active flash
+func (c *Crosshair) Tick(deltaTime float32) {
+	if c.flashTime <= 0 {
+		return
+	}
+	c.flashTime -= deltaTime
+	if c.flashTime <= 0 {
+		c.SetColor(c.baseColor)
+	}
+}
+
 func (c *Crosshair) SetSize(size float32) {
 	c.size = size
 	c.generateGeometry()
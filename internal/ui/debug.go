@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 
+	"voxel-game/internal/render"
+
 	"github.com/go-gl/mathgl/mgl32"
 )
 
@@ -17,6 +19,9 @@ type DebugLayer struct {
 	memText      *Text
 	statsText    *Text
 	targetText   *Text
+	cullText     *Text
+	timeText     *Text
+	seedText     *Text
 }
 
 func NewDebugLayer(font *Font, width, height int) *DebugLayer {
@@ -31,6 +36,9 @@ func NewDebugLayer(font *Font, width, height int) *DebugLayer {
 		memText:      NewText(font, "Mem: 0MB", 10, 110, 0.5, mgl32.Vec3{1, 1, 1}),
 		statsText:    NewText(font, "Render: -", 10, 130, 0.5, mgl32.Vec3{1, 1, 1}),
 		targetText:   NewText(font, "Target: -", 10, 150, 0.5, mgl32.Vec3{1, 1, 1}),
+		cullText:     NewText(font, "Cull: ON", 10, 170, 0.5, mgl32.Vec3{1, 1, 1}),
+		timeText:     NewText(font, "Time: 00:00", 10, 190, 0.5, mgl32.Vec3{1, 1, 1}),
+		seedText:     NewText(font, "Seed: 0", 10, 210, 0.5, mgl32.Vec3{1, 1, 1}),
 	}
 }
 
@@ -43,6 +51,9 @@ func (d *DebugLayer) Init() error {
 	d.memText.Init()
 	d.statsText.Init()
 	d.targetText.Init()
+	d.cullText.Init()
+	d.timeText.Init()
+	d.seedText.Init()
 	return nil
 }
 
@@ -57,6 +68,9 @@ func (d *DebugLayer) Update(state interface{}) {
 	d.memText.Update(nil)
 	d.statsText.Update(nil)
 	d.targetText.Update(nil)
+	d.cullText.Update(nil)
+	d.timeText.Update(nil)
+	d.seedText.Update(nil)
 }
 
 func (d *DebugLayer) Draw(shader uint32, proj mgl32.Mat4) {
@@ -71,6 +85,9 @@ func (d *DebugLayer) Draw(shader uint32, proj mgl32.Mat4) {
 	d.memText.Draw(shader, proj)
 	d.statsText.Draw(shader, proj)
 	d.targetText.Draw(shader, proj)
+	d.cullText.Draw(shader, proj)
+	d.timeText.Draw(shader, proj)
+	d.seedText.Draw(shader, proj)
 }
 
 func (d *DebugLayer) Cleanup() {
@@ -81,6 +98,9 @@ func (d *DebugLayer) Cleanup() {
 	d.memText.Cleanup()
 	d.statsText.Cleanup()
 	d.targetText.Cleanup()
+	d.cullText.Cleanup()
+	d.timeText.Cleanup()
+	d.seedText.Cleanup()
 }
 
 func (d *DebugLayer) Toggle() bool {
@@ -88,23 +108,20 @@ func (d *DebugLayer) Toggle() bool {
 	return d.visible
 }
 
-func (d *DebugLayer) UpdateInfo(fps float64,
-	frameTime float32,
-	pos mgl32.Vec3,
-	facing mgl32.Vec3, // RENAMED from 'dir' to 'facing' to match your logic below
-	chunkX, chunkZ int,
-	memMB uint64,
-	goroutines int,
-	renderedChunks int,
-	totalVerts int32,
-	targetBlock string) {
+func (d *DebugLayer) IsVisible() bool {
+	return d.visible
+}
+
+// UpdateInfo refreshes the overlay text, only while it is visible
+func (d *DebugLayer) UpdateInfo(info DebugInfo, stats render.RenderStats) {
 	if !d.visible {
 		return
 	}
-	d.fpsText.SetContent(fmt.Sprintf("FPS: %.0f (%.2f ms)", fps, frameTime*1000))
-	d.positionText.SetContent(fmt.Sprintf("Pos: %.1f, %.1f, %.1f", pos.X(), pos.Y(), pos.Z()))
-	d.chunkText.SetContent(fmt.Sprintf("Chunk: %d, %d", chunkX, chunkZ))
+	d.fpsText.SetContent(fmt.Sprintf("FPS: %.0f (%.2f ms) %s", info.FPS, info.FrameTime*1000, info.FrameMode))
+	d.positionText.SetContent(fmt.Sprintf("Pos: %.1f, %.1f, %.1f", info.Position.X(), info.Position.Y(), info.Position.Z()))
+	d.chunkText.SetContent(fmt.Sprintf("Chunk: %d, %d | View: %d", info.ChunkX, info.ChunkZ, info.RenderDistance))
 
+	facing := info.Facing
 	directionStr := "North"
 	if abs(facing.X()) > abs(facing.Z()) {
 		if facing.X() > 0 {
@@ -119,13 +136,23 @@ func (d *DebugLayer) UpdateInfo(fps float64,
 			directionStr = "North"
 		}
 	}
-	d.facingText.SetContent(fmt.Sprintf("Facing: %s", directionStr))
+	d.facingText.SetContent(fmt.Sprintf("Facing: %s | Biome: %s", directionStr, info.Biome))
 
-	d.memText.SetContent(fmt.Sprintf("Mem: %d MB | GRT: %d", memMB, goroutines))
+	d.memText.SetContent(fmt.Sprintf("Mem: %d MB | GRT: %d", info.MemMB, info.Goroutines))
 
-	d.statsText.SetContent(fmt.Sprintf("Render: %d Chunks | %dk Verts", renderedChunks, totalVerts/1000))
+	d.statsText.SetContent(fmt.Sprintf("Render: %d/%d Chunks (%d culled) | %d Draws | %dk Verts | %d Particles",
+		stats.ChunksRendered, stats.ChunksLoaded, stats.ChunksCulled, stats.DrawCalls, stats.TotalVertices/1000, info.Particles))
+
+	d.targetText.SetContent(fmt.Sprintf("Target: %s", info.Target))
+
+	if info.CullFaces {
+		d.cullText.SetContent("Cull: ON")
+	} else {
+		d.cullText.SetContent("Cull: OFF")
+	}
 
-	d.targetText.SetContent(fmt.Sprintf("Target: %s", targetBlock))
+	d.timeText.SetContent(fmt.Sprintf("Time: %s", info.TimeOfDay))
+	d.seedText.SetContent(fmt.Sprintf("Seed: %d", info.Seed))
 }
 
 func abs(x float32) float32 {
@@ -3,8 +3,11 @@ package ui
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"os"
 
+	"voxel-game/internal/errs"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/golang/freetype"
@@ -26,39 +29,58 @@ type Font struct {
 	Glyphs     map[rune]GlyphInfo
 	LineHeight float32
 	Ascent     float32
+
+	// notdef stands in for characters the atlas doesn't have
+	notdef GlyphInfo
+}
+
+// RuneRange is an inclusive range of characters to bake into a font atlas
+type RuneRange struct {
+	First, Last rune
+}
+
+// Character sets for LoadFont
+var (
+	ASCII            = RuneRange{0x20, 0x7E}
+	Latin1Supplement = RuneRange{0xA0, 0xFF}
+)
+
+// DefaultRunes are baked when LoadFont is given no ranges
+var DefaultRunes = []RuneRange{ASCII, Latin1Supplement}
+
+// Atlas sizes tried while packing, it doubles until everything fits
+const (
+	minAtlasSize = 256
+	maxAtlasSize = 4096
+)
+
+// atlasGlyph is a glyph waiting to be packed, x, y is where it landed
+type atlasGlyph struct {
+	ch      rune
+	bounds  fixed.Rectangle26_6
+	advance fixed.Int26_6
+	w, h    int
+	x, y    int
 }
 
-func LoadFont(filePath string, fontSize float64, smooth bool) (*Font, error) {
+// LoadFont bakes the characters in ranges into an atlas texture, DefaultRunes
+// if none are given. Characters the font lacks, and any not baked, draw as a
+// hollow box.
+func LoadFont(filePath string, fontSize float64, smooth bool, ranges ...RuneRange) (*Font, error) {
 	// Read font file
 	fontBytes, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("could not read font file: %w", err)
+		return nil, &errs.AssetLoadError{Path: filePath, Cause: err}
 	}
 
 	f, err := freetype.ParseFont(fontBytes)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse font: %w", err)
+		return nil, &errs.AssetLoadError{Path: filePath, Cause: fmt.Errorf("could not parse font: %w", err)}
 	}
-
-	// Setup Atlas Image (512x512 for basic ASCII)
-	const atlasSize = 1024
-	const padding = 2
-	atlasImg := image.NewRGBA(image.Rect(0, 0, atlasSize, atlasSize))
-
-	for i := range atlasImg.Pix {
-		atlasImg.Pix[i] = 0
+	if len(ranges) == 0 {
+		ranges = DefaultRunes
 	}
 
-	// Context for drawing text
-	c := freetype.NewContext()
-	c.SetDPI(72)
-	c.SetFont(f)
-	c.SetFontSize(fontSize)
-	c.SetClip(atlasImg.Bounds())
-	c.SetDst(atlasImg)
-	c.SetSrc(image.White)
-	c.SetHinting(font.HintingNone)
-
 	opts := truetype.Options{
 		Size:    fontSize,
 		DPI:     72,
@@ -71,73 +93,111 @@ func LoadFont(filePath string, fontSize float64, smooth bool) (*Font, error) {
 	descent := float32(metrics.Descent) / 64.0
 	lineHeight := ascent + descent
 
-	// debug
-	fmt.Printf("=== FONT METRICS DEBUG ===\n")
-	fmt.Printf("Font: %s, Size: %.1f\n", filePath, fontSize)
-	fmt.Printf("Ascent: %.2f\n", ascent)
-	fmt.Printf("Descent: %.2f\n", descent)
-	fmt.Printf("LineHeight: %.2f\n", lineHeight)
+	debugf("=== FONT METRICS DEBUG ===\n")
+	debugf("Font: %s, Size: %.1f\n", filePath, fontSize)
+	debugf("Ascent: %.2f\n", ascent)
+	debugf("Descent: %.2f\n", descent)
+	debugf("LineHeight: %.2f\n", lineHeight)
+
+	// Measure every glyph the font has. Index 0 is the font's own
+	// .notdef, which is often blank, so those are left to the box below.
+	var pending []atlasGlyph
+	seen := make(map[rune]bool)
+	for _, r := range ranges {
+		for ch := r.First; ch <= r.Last; ch++ {
+			if seen[ch] || f.Index(ch) == 0 {
+				continue
+			}
+			seen[ch] = true
+			b, advance, ok := face.GlyphBounds(ch)
+			if !ok {
+				continue
+			}
+			pending = append(pending, atlasGlyph{
+				ch:      ch,
+				bounds:  b,
+				advance: advance,
+				w:       (b.Max.X - b.Min.X).Ceil(),
+				h:       (b.Max.Y - b.Min.Y).Ceil(),
+			})
+		}
+	}
 
-	// Render Glyphs
-	glyphs := make(map[rune]GlyphInfo)
+	// The missing glyph box, about as tall as a capital
+	boxW := max(3, int(ascent*0.5+0.5))
+	boxH := max(4, int(ascent*0.75+0.5))
+	pending = append(pending, atlasGlyph{ch: -1, w: boxW, h: boxH})
+
+	atlasSize := minAtlasSize
+	for !packGlyphs(pending, atlasSize) {
+		if atlasSize >= maxAtlasSize {
+			return nil, &errs.AssetLoadError{Path: filePath, Cause: fmt.Errorf(
+				"%d glyphs at size %.0f do not fit a %dx%d font atlas, bake fewer characters or reduce fontSize",
+				len(pending), fontSize, maxAtlasSize, maxAtlasSize)}
+		}
+		atlasSize *= 2
+	}
+	debugf("Atlas: %d glyphs in %dx%d\n", len(pending), atlasSize, atlasSize)
 
-	currentX := padding
-	currentY := padding
-	maxRowHeight := 0
+	// Glyphs are plain coverage, one byte a texel
+	atlasImg := image.NewAlpha(image.Rect(0, 0, atlasSize, atlasSize))
 
-	// Render ASCII range 32 (space) to 126 (~)
-	for ch := rune(32); ch <= 126; ch++ {
-		b, advance, ok := face.GlyphBounds(ch)
-		if !ok {
-			continue // Glyph missing
-		}
+	// Context for drawing text
+	c := freetype.NewContext()
+	c.SetDPI(72)
+	c.SetFont(f)
+	c.SetFontSize(fontSize)
+	c.SetClip(atlasImg.Bounds())
+	c.SetDst(atlasImg)
+	c.SetSrc(image.White)
+	c.SetHinting(font.HintingNone)
 
-		gw := (b.Max.X - b.Min.X).Ceil()
-		gh := (b.Max.Y - b.Min.Y).Ceil()
+	// Render Glyphs
+	glyphs := make(map[rune]GlyphInfo, len(pending))
+	var notdef GlyphInfo
 
-		if currentX+gw+padding >= atlasSize {
-			currentX = 0
-			currentY += maxRowHeight + padding
-			maxRowHeight = 0
+	for _, g := range pending {
+		halfTexel := 0.5 / float32(atlasSize)
+		// Normalize pixel coordinates to 0.0-1.0 range
+		uMin := float32(g.x)/float32(atlasSize) + halfTexel
+		vMin := float32(g.y)/float32(atlasSize) + halfTexel
+		uMax := float32(g.x+g.w)/float32(atlasSize) - halfTexel
+		vMax := float32(g.y+g.h)/float32(atlasSize) - halfTexel
+
+		if g.ch < 0 {
+			drawNotdefBox(atlasImg, g.x, g.y, g.w, g.h, max(1, int(fontSize/16)))
+			notdef = GlyphInfo{
+				UVMin:   mgl32.Vec2{uMin, vMin},
+				UVMax:   mgl32.Vec2{uMax, vMax},
+				Size:    mgl32.Vec2{float32(g.w), float32(g.h)},
+				Bearing: mgl32.Vec2{1, float32(g.h)},
+				Advance: float32(g.w + 2),
+			}
+			continue
 		}
 
-		if currentY+gh+padding >= atlasSize {
-			return nil, fmt.Errorf("font atlas full (increase atlasSize or reduce fontSize)")
-		}
-		dotX := currentX - b.Min.X.Floor()
-		dotY := currentY - b.Min.Y.Floor()
+		b := g.bounds
+		dotX := g.x - b.Min.X.Floor()
+		dotY := g.y - b.Min.Y.Floor()
 
 		pt := fixed.P(dotX, dotY)
-		c.DrawString(string(ch), pt)
-
-		halfTexel := 0.5 / float32(atlasSize)
-		// Normalize pixel coordinates to 0.0-1.0 range
-		uMin := float32(currentX)/float32(atlasSize) + halfTexel
-		vMin := float32(currentY)/float32(atlasSize) + halfTexel
-		uMax := float32(currentX+gw)/float32(atlasSize) - halfTexel
-		vMax := float32(currentY+gh)/float32(atlasSize) - halfTexel
+		c.DrawString(string(g.ch), pt)
 
 		bearingX := float32(b.Min.X) / 64.0
 		bearingY := float32(b.Max.Y) / 64.0
 
-		// debug
-		if ch == 'A' || ch == 'g' || ch == 'y' || ch == 'M' || ch == 'p' {
+		// Glyphs that show the baseline and descender handling
+		if Debug && (g.ch == 'A' || g.ch == 'g' || g.ch == 'y' || g.ch == 'M' || g.ch == 'p') {
 			fmt.Printf("Char '%c': b.Min.Y=%.2f, b.Max.Y=%.2f, bearingY=%.2f, size=(%.0f,%.0f)\n",
-				ch, float32(b.Min.Y)/64.0, float32(b.Max.Y)/64.0, bearingY, float32(gw), float32(gh))
+				g.ch, float32(b.Min.Y)/64.0, float32(b.Max.Y)/64.0, bearingY, float32(g.w), float32(g.h))
 		}
 
-		glyphs[ch] = GlyphInfo{
+		glyphs[g.ch] = GlyphInfo{
 			UVMin:   mgl32.Vec2{uMin, vMin},
 			UVMax:   mgl32.Vec2{uMax, vMax},
-			Size:    mgl32.Vec2{float32(gw), float32(gh)},
+			Size:    mgl32.Vec2{float32(g.w), float32(g.h)},
 			Bearing: mgl32.Vec2{bearingX, bearingY},
-			Advance: float32(advance) / 64.0,
-		}
-
-		// Advance packing cursor
-		currentX += gw + padding
-		if gh > maxRowHeight {
-			maxRowHeight = gh
+			Advance: float32(g.advance) / 64.0,
 		}
 	}
 
@@ -151,11 +211,11 @@ func LoadFont(filePath string, fontSize float64, smooth bool) (*Font, error) {
 	gl.TexImage2D(
 		gl.TEXTURE_2D,
 		0,
-		gl.RGBA,
+		gl.R8,
 		int32(atlasSize),
 		int32(atlasSize),
 		0,
-		gl.RGBA,
+		gl.RED,
 		gl.UNSIGNED_BYTE,
 		gl.Ptr(atlasImg.Pix),
 	)
@@ -180,6 +240,74 @@ func LoadFont(filePath string, fontSize float64, smooth bool) (*Font, error) {
 		Glyphs:     glyphs,
 		LineHeight: lineHeight,
 		Ascent:     ascent,
+		notdef:     notdef,
 	}, nil
+}
+
+// packGlyphs places glyphs in rows across a size x size atlas, reporting
+// whether they all fit
+func packGlyphs(glyphs []atlasGlyph, size int) bool {
+	const padding = 2
+	currentX := padding
+	currentY := padding
+	maxRowHeight := 0
 
+	for i := range glyphs {
+		g := &glyphs[i]
+		if currentX+g.w+padding >= size {
+			currentX = padding
+			currentY += maxRowHeight + padding
+			maxRowHeight = 0
+		}
+		if g.w+2*padding >= size || currentY+g.h+padding >= size {
+			return false
+		}
+		g.x, g.y = currentX, currentY
+
+		// Advance packing cursor
+		currentX += g.w + padding
+		maxRowHeight = max(maxRowHeight, g.h)
+	}
+	return true
+}
+
+// drawNotdefBox outlines a w x h box at x, y, thickness pixels wide
+func drawNotdefBox(img *image.Alpha, x, y, w, h, thickness int) {
+	for py := y; py < y+h; py++ {
+		for px := x; px < x+w; px++ {
+			if px-x >= thickness && x+w-1-px >= thickness && py-y >= thickness && y+h-1-py >= thickness {
+				continue
+			}
+			img.SetAlpha(px, py, color.Alpha{255})
+		}
+	}
+}
+
+// Delete frees the font's atlas texture
+func (f *Font) Delete() {
+	gl.DeleteTextures(1, &f.TextureID)
+	f.TextureID = 0
+}
+
+// Glyph is the atlas entry for ch, the missing glyph box if there is none
+func (f *Font) Glyph(ch rune) GlyphInfo {
+	if glyph, ok := f.Glyphs[ch]; ok {
+		return glyph
+	}
+	return f.notdef
+}
+
+// MeasureString is how wide s renders at scale, the sum of its glyph
+// advances. With several lines it is the widest line.
+func (f *Font) MeasureString(s string, scale float32) float32 {
+	widest, width := float32(0), float32(0)
+	for _, ch := range s {
+		if ch == '\n' {
+			widest = max(widest, width)
+			width = 0
+			continue
+		}
+		width += f.Glyph(ch).Advance * scale
+	}
+	return max(widest, width)
 }
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// HealthBar is a row of hearts centered above the hotbar, two health per
+// heart. Geometry is only rebuilt when health changes.
+type HealthBar struct {
+	vao uint32
+	vbo uint32
+
+	screenWidth  int
+	screenHeight int
+	visible      bool
+
+	health    float32
+	maxHealth float32
+
+	heartSize float32
+	spacing   float32
+
+	vertexCount int
+	needsUpdate bool
+
+	texture uint32
+}
+
+var (
+	heartOutline = mgl32.Vec3{0.1, 0.0, 0.0}
+	heartFull    = mgl32.Vec3{0.85, 0.1, 0.1}
+	heartEmpty   = mgl32.Vec3{0.3, 0.08, 0.08}
+)
+
+func NewHealthBar(screenWidth, screenHeight int) *HealthBar {
+	return &HealthBar{
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		visible:      true,
+		heartSize:    14.0,
+		spacing:      3.0,
+		needsUpdate:  true,
+	}
+}
+
+func (hb *HealthBar) Init() error {
+	gl.GenVertexArrays(1, &hb.vao)
+	gl.GenBuffers(1, &hb.vbo)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &hb.texture)
+	gl.BindTexture(gl.TEXTURE_2D, hb.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	checkGLError("HealthBar.Init")
+	return nil
+}
+
+func (hb *HealthBar) SetVisible(visible bool) {
+	hb.visible = visible
+}
+
+func (hb *HealthBar) Update(state interface{}) {
+	if screenSize, ok := state.(*ScreenSize); ok {
+		hb.screenWidth = screenSize.Width
+		hb.screenHeight = screenSize.Height
+		hb.needsUpdate = true
+	}
+	if healthState, ok := state.(HealthState); ok {
+		if healthState.Health != hb.health || healthState.MaxHealth != hb.maxHealth {
+			hb.health = healthState.Health
+			hb.maxHealth = healthState.MaxHealth
+			hb.needsUpdate = true
+		}
+	}
+
+	if hb.needsUpdate {
+		hb.generateGeometry()
+	}
+}
+
+func (hb *HealthBar) generateGeometry() {
+	hearts := int(math.Ceil(float64(hb.maxHealth / 2)))
+	totalWidth := float32(hearts)*hb.heartSize + float32(max(0, hearts-1))*hb.spacing
+	startX := (float32(hb.screenWidth) - totalWidth) / 2.0
+	// Just above the hotbar, which starts 80 pixels from the bottom
+	y := float32(hb.screenHeight) - 80.0 - 8.0 - hb.heartSize
+
+	vertices := make([]float32, 0)
+	inner := hb.heartSize - 4
+	for i := 0; i < hearts; i++ {
+		x := startX + float32(i)*(hb.heartSize+hb.spacing)
+		vertices = append(vertices, createFilledRect(x, y, hb.heartSize, hb.heartSize, heartOutline)...)
+
+		// How much of this heart is left, 0, half or whole
+		fill := min(max(hb.health-float32(i*2), 0), 2) / 2
+		switch {
+		case fill >= 1:
+			vertices = append(vertices, createFilledRect(x+2, y+2, inner, inner, heartFull)...)
+		case fill > 0:
+			vertices = append(vertices, createFilledRect(x+2, y+2, inner, inner, heartEmpty)...)
+			vertices = append(vertices, createFilledRect(x+2, y+2, inner*fill, inner, heartFull)...)
+		default:
+			vertices = append(vertices, createFilledRect(x+2, y+2, inner, inner, heartEmpty)...)
+		}
+	}
+
+	hb.vertexCount = len(vertices) / 7
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(hb.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, hb.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	hb.needsUpdate = false
+	checkGLError("HealthBar.generateGeometry")
+}
+
+func (hb *HealthBar) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	if !hb.visible || hb.vertexCount == 0 {
+		return
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, hb.texture)
+
+	gl.BindVertexArray(hb.vao)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(hb.vertexCount))
+	gl.BindVertexArray(0)
+
+	checkGLError("HealthBar.Draw")
+}
+
+func (hb *HealthBar) Cleanup() {
+	gl.DeleteVertexArrays(1, &hb.vao)
+	gl.DeleteBuffers(1, &hb.vbo)
+	gl.DeleteTextures(1, &hb.texture)
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"strconv"
+
 	"voxel-game/internal/world"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -16,29 +18,47 @@ type Hotbar struct {
 
 	selectedSlot int
 	slotCount    int
+	slots        []world.BlockType
+	counts       []int
+	countTexts   []*Text
 	slotSize     float32
 	padding      float32
 
 	needsUpdate bool
 
 	fillVertexCount   int
+	iconVertexCount   int
 	borderVertexCount int
 
 	texture uint32
+	// World texture atlas the block icons are drawn from
+	atlasTexture uint32
 }
 
-func NewHotbar(screenWidth, screenHeight int) *Hotbar {
+func NewHotbar(font *Font, atlasTexture uint32, screenWidth, screenHeight, slotCount int) *Hotbar {
+	countTexts := make([]*Text, slotCount)
+	for i := range countTexts {
+		countTexts[i] = NewText(font, "", 0, 0, countScale, mgl32.Vec3{1, 1, 1})
+		countTexts[i].SetAlignment(AlignRight)
+	}
 	return &Hotbar{
 		screenWidth:  screenWidth,
 		screenHeight: screenHeight,
-		selectedSlot: 0, // Dirt by default
-		slotCount:    6, // 3 block types
+		selectedSlot: 0,
+		slotCount:    slotCount,
+		slots:        make([]world.BlockType, slotCount),
+		counts:       make([]int, slotCount),
+		countTexts:   countTexts,
+		atlasTexture: atlasTexture,
 		slotSize:     50.0,
 		padding:      5.0,
 		needsUpdate:  true,
 	}
 }
 
+// countScale sizes the stack count drawn in the corner of each slot
+const countScale = 0.6
+
 func (h *Hotbar) Init() error {
 	gl.GenVertexArrays(1, &h.vao)
 	gl.GenBuffers(1, &h.vbo)
@@ -52,6 +72,9 @@ func (h *Hotbar) Init() error {
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
 
+	for _, text := range h.countTexts {
+		text.Init()
+	}
 	h.generateGeometry()
 	return nil
 }
@@ -63,6 +86,7 @@ func (h *Hotbar) generateGeometry() {
 	bottomY := float32(h.screenHeight) - 80.0 // 80 pixels from bottom
 
 	fillVertices := make([]float32, 0)
+	iconVertices := make([]float32, 0)
 	borderVertices := make([]float32, 0)
 
 	borderThickness := float32(2.0)
@@ -80,10 +104,7 @@ func (h *Hotbar) generateGeometry() {
 			borderColor = mgl32.Vec3{0.5, 0.5, 0.5} // Gray for unselected
 		}
 
-		// Get block color for the fill
-		blockColor := getBlockColorForSlot(i)
-
-		// Draw filled rectangle (block preview)
+		// Dark slot background with the block drawn over it
 		innerPadding := float32(5.0)
 		if i == h.selectedSlot {
 			innerPadding = 3.0 // Less padding for selected
@@ -94,7 +115,11 @@ func (h *Hotbar) generateGeometry() {
 			bottomY+innerPadding,
 			h.slotSize-innerPadding*2,
 			h.slotSize-innerPadding*2,
-			blockColor)...)
+			emptySlotColor)...)
+		if h.slots[i] != world.BlockAir {
+			iconVertices = appendBlockIcon(iconVertices, h.slots[i],
+				x+h.slotSize/2, bottomY+h.slotSize/2, h.slotSize-innerPadding*2-6)
+		}
 
 		// Draw border as 4 thin rectangles
 		// Top border
@@ -124,9 +149,12 @@ func (h *Hotbar) generateGeometry() {
 			borderThickness,
 			h.slotSize,
 			borderColor)...)
+
+		h.layoutCount(i, x, bottomY)
 	}
 
 	h.fillVertexCount = len(fillVertices) / 7
+	h.iconVertexCount = len(iconVertices) / 7
 	h.borderVertexCount = len(borderVertices) / 7
 	stride := int32(7 * 4)
 
@@ -134,7 +162,7 @@ func (h *Hotbar) generateGeometry() {
 	gl.BindVertexArray(h.vao)
 	gl.BindBuffer(gl.ARRAY_BUFFER, h.vbo)
 
-	combined := append(fillVertices, borderVertices...)
+	combined := append(append(fillVertices, iconVertices...), borderVertices...)
 	gl.BufferData(gl.ARRAY_BUFFER, len(combined)*4, gl.Ptr(combined), gl.DYNAMIC_DRAW)
 
 	// Position attribute (2D)
@@ -157,12 +185,23 @@ func (h *Hotbar) generateGeometry() {
 }
 
 func (h *Hotbar) Update(state interface{}) {
-	if selectedBlock, ok := state.(world.BlockType); ok {
-		newSlot := int(selectedBlock) - 1 // BlockDirt=1 -> slot 0
-		if newSlot >= 0 && newSlot < h.slotCount && newSlot != h.selectedSlot {
-			h.selectedSlot = newSlot
+	if hotbarState, ok := state.(HotbarState); ok {
+		if hotbarState.Selected >= 0 && hotbarState.Selected < h.slotCount && hotbarState.Selected != h.selectedSlot {
+			h.selectedSlot = hotbarState.Selected
 			h.needsUpdate = true
 		}
+		for i := 0; i < h.slotCount && i < len(hotbarState.Slots); i++ {
+			if h.slots[i] != hotbarState.Slots[i] {
+				h.slots[i] = hotbarState.Slots[i]
+				h.needsUpdate = true
+			}
+		}
+		for i := 0; i < h.slotCount && i < len(hotbarState.Counts); i++ {
+			if h.counts[i] != hotbarState.Counts[i] {
+				h.counts[i] = hotbarState.Counts[i]
+				h.needsUpdate = true
+			}
+		}
 	}
 
 	if screenSize, ok := state.(*ScreenSize); ok {
@@ -187,34 +226,81 @@ func (h *Hotbar) Draw(shaderProgram uint32, projection mgl32.Mat4) {
 	// Draw fill batch
 	gl.DrawArrays(gl.TRIANGLES, 0, int32(h.fillVertexCount))
 
+	// Draw block icons from the atlas
+	gl.BindTexture(gl.TEXTURE_2D, h.atlasTexture)
+	gl.DrawArrays(gl.TRIANGLES, int32(h.fillVertexCount), int32(h.iconVertexCount))
+	gl.BindTexture(gl.TEXTURE_2D, h.texture)
+
 	// Draw border batch
-	gl.DrawArrays(gl.TRIANGLES, int32(h.fillVertexCount), int32(h.borderVertexCount))
+	gl.DrawArrays(gl.TRIANGLES, int32(h.fillVertexCount+h.iconVertexCount), int32(h.borderVertexCount))
 
 	gl.BindVertexArray(0)
 
+	for _, text := range h.countTexts {
+		text.Draw(shaderProgram, projection)
+	}
+
 	checkGLError("Hotbar.Draw")
 }
 
 func (h *Hotbar) Cleanup() {
 	gl.DeleteVertexArrays(1, &h.vao)
 	gl.DeleteBuffers(1, &h.vbo)
+	for _, text := range h.countTexts {
+		text.Cleanup()
+	}
+}
+
+// layoutCount right-aligns the stack count in the bottom corner of the slot
+// at x, y. A single item or an empty slot shows no number.
+func (h *Hotbar) layoutCount(i int, x, y float32) {
+	text := h.countTexts[i]
+	content := ""
+	if h.counts[i] > 1 {
+		content = strconv.Itoa(h.counts[i])
+	}
+	text.content = content
+	text.x = x + h.slotSize - 6
+	text.y = y + h.slotSize - 6 // baseline
+	text.generateGeometry()
+}
+
+var emptySlotColor = mgl32.Vec3{0.2, 0.2, 0.2}
+
+// appendBlockIcon adds a small isometric cube of blockType, size pixels
+// across and centered on cx, cy, textured from the atlas. The sides are
+// shaded darker than the top so the cube reads as solid.
+func appendBlockIcon(vertices []float32, blockType world.BlockType, cx, cy, size float32) []float32 {
+	a := size / 2 // Half the width, also the height of a side edge
+	q := a / 2    // Rise of the top face's slanted edges
+	top := cy - a
+
+	t := mgl32.Vec2{cx, top}
+	l := mgl32.Vec2{cx - a, top + q}
+	r := mgl32.Vec2{cx + a, top + q}
+	c := mgl32.Vec2{cx, top + 2*q}
+	bl := mgl32.Vec2{cx - a, top + q + a}
+	br := mgl32.Vec2{cx + a, top + q + a}
+	b := mgl32.Vec2{cx, top + 2*q + a}
+
+	// Faces: 4 top, 0 front on the left, 2 right
+	vertices = appendIconFace(vertices, blockType, 4, [4]mgl32.Vec2{t, r, c, l}, 1.0)
+	vertices = appendIconFace(vertices, blockType, 0, [4]mgl32.Vec2{l, c, b, bl}, 0.8)
+	vertices = appendIconFace(vertices, blockType, 2, [4]mgl32.Vec2{c, r, br, b}, 0.6)
+	return vertices
 }
 
-func getBlockColorForSlot(slot int) mgl32.Vec3 {
-	switch slot {
-	case 0: // Dirt
-		return mgl32.Vec3{0.6, 0.4, 0.2}
-	case 1: // Grass
-		return mgl32.Vec3{0.2, 0.8, 0.2}
-	case 2: // Stone
-		return mgl32.Vec3{0.5, 0.5, 0.5}
-	case 3: // Snow
-		return mgl32.Vec3{1.0, 1.0, 1.0}
-	case 4: // Sand
-		return mgl32.Vec3{0.9, 0.8, 0.6}
-	case 5: // Wood
-		return mgl32.Vec3{0.5, 0.3, 0.1}
-	default:
-		return mgl32.Vec3{1.0, 1.0, 1.0}
+// appendIconFace maps a face's atlas tile onto a parallelogram, corners
+// in the order of the tile's top-left, top-right, bottom-right, bottom-left
+func appendIconFace(vertices []float32, blockType world.BlockType, face int, corners [4]mgl32.Vec2, shade float32) []float32 {
+	u0, v0, u1, v1 := world.TileUVRect(world.BlockTile(blockType, 0, face))
+	// Pull in half a texel so minified icons don't pick up neighboring tiles
+	du, dv := float32(0.5)/world.TextureWidth, float32(0.5)/world.TextureHeight
+	u0, v0, u1, v1 = u0+du, v0+dv, u1-du, v1-dv
+	uvs := [4]mgl32.Vec2{{u0, v0}, {u1, v0}, {u1, v1}, {u0, v1}}
+
+	for _, i := range [6]int{0, 1, 2, 0, 2, 3} {
+		vertices = append(vertices, corners[i].X(), corners[i].Y(), shade, shade, shade, uvs[i].X(), uvs[i].Y())
 	}
+	return vertices
 }
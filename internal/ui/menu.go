@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// MenuButton is one entry in a Menu page
+type MenuButton struct {
+	Label   string
+	OnClick func()
+}
+
+// Menu is a full-screen overlay that dims the world and shows a title over
+// a column of clickable buttons, e.g. the pause menu. Pages are swapped with
+// SetPage.
+type Menu struct {
+	font *Font
+
+	screenWidth  int
+	screenHeight int
+	visible      bool
+
+	title *Text
+	page  []MenuButton
+	// Buttons are reused between pages, only the first len(page) are shown
+	buttons []*Button
+
+	buttonWidth  float32
+	buttonHeight float32
+	spacing      float32
+
+	vao         uint32
+	vbo         uint32
+	vertexCount int
+	needsUpdate bool
+
+	texture uint32
+}
+
+// Shade multiplied into the world behind the menu
+var menuDim = mgl32.Vec3{0.4, 0.4, 0.4}
+
+func NewMenu(font *Font, screenWidth, screenHeight int) *Menu {
+	title := NewText(font, "", 0, 0, 1.5, mgl32.Vec3{1, 1, 1})
+	title.SetAlignment(AlignCenter)
+	return &Menu{
+		font:         font,
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		title:        title,
+		buttonWidth:  260.0,
+		buttonHeight: 40.0,
+		spacing:      12.0,
+		needsUpdate:  true,
+	}
+}
+
+func (m *Menu) Init() error {
+	gl.GenVertexArrays(1, &m.vao)
+	gl.GenBuffers(1, &m.vbo)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &m.texture)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	m.title.Init()
+
+	checkGLError("Menu.Init")
+	return nil
+}
+
+func (m *Menu) SetVisible(visible bool) {
+	if m.visible && !visible {
+		for _, button := range m.buttons {
+			button.Reset()
+		}
+	}
+	m.visible = visible
+}
+
+func (m *Menu) IsVisible() bool {
+	return m.visible
+}
+
+// SetPage replaces the title and buttons. Labels can be changed by setting
+// the same page again, e.g. after a click toggles an option.
+func (m *Menu) SetPage(title string, buttons []MenuButton) {
+	m.title.SetContent(title)
+	m.page = append(m.page[:0], buttons...)
+	m.needsUpdate = true
+}
+
+func (m *Menu) Update(state interface{}) {
+	if screenSize, ok := state.(*ScreenSize); ok {
+		m.screenWidth = screenSize.Width
+		m.screenHeight = screenSize.Height
+		m.needsUpdate = true
+		return
+	}
+	if m.needsUpdate && m.visible {
+		m.generateGeometry()
+	}
+}
+
+// visibleButtons are the buttons of the current page, once laid out
+func (m *Menu) visibleButtons() []*Button {
+	return m.buttons[:min(len(m.page), len(m.buttons))]
+}
+
+func (m *Menu) HandleCursor(x, y float32) {
+	if !m.visible {
+		return
+	}
+	for _, button := range m.visibleButtons() {
+		button.HandleCursor(x, y)
+	}
+}
+
+// HandleClick passes the click to the buttons. The open menu takes every
+// click, hit or miss, so nothing behind it reacts.
+func (m *Menu) HandleClick(x, y float32, pressed bool) bool {
+	if !m.visible {
+		return false
+	}
+	for _, button := range m.visibleButtons() {
+		// A click may switch pages, which relabels these same buttons
+		if button.HandleClick(x, y, pressed) {
+			break
+		}
+	}
+	return true
+}
+
+func (m *Menu) generateGeometry() {
+	// Buttons stacked in the middle of the screen, the title above them
+	count := float32(len(m.page))
+	totalHeight := count*m.buttonHeight + max(0, count-1)*m.spacing
+	startX := (float32(m.screenWidth) - m.buttonWidth) / 2.0
+	startY := (float32(m.screenHeight) - totalHeight) / 2.0
+
+	vertices := make([]float32, 0, 6*7)
+	vertices = append(vertices, createFilledRect(0, 0, float32(m.screenWidth), float32(m.screenHeight), menuDim)...)
+
+	for i, entry := range m.page {
+		y := startY + float32(i)*(m.buttonHeight+m.spacing)
+		if i >= len(m.buttons) {
+			button := NewButton(m.font, "", 0, 0, 0, 0, nil)
+			button.Init()
+			m.buttons = append(m.buttons, button)
+		}
+		button := m.buttons[i]
+		button.SetLabel(entry.Label)
+		button.SetBounds(startX, y, m.buttonWidth, m.buttonHeight)
+		button.OnClick = entry.OnClick
+	}
+
+	// Title centered above the buttons
+	m.title.x = float32(m.screenWidth) / 2
+	m.title.y = startY - 50 + m.font.Ascent*m.title.scale/2
+	m.title.generateGeometry()
+
+	m.vertexCount = len(vertices) / 7
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(m.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	m.needsUpdate = false
+	checkGLError("Menu.generateGeometry")
+}
+
+func (m *Menu) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	if !m.visible {
+		return
+	}
+	// Opened or changed page since the last Update
+	if m.needsUpdate {
+		m.generateGeometry()
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+	gl.BindVertexArray(m.vao)
+
+	// The UI shader has no alpha, so dim by multiplying the world's color
+	gl.BlendFunc(gl.ZERO, gl.SRC_COLOR)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(m.vertexCount))
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(0)
+
+	m.title.Draw(shaderProgram, projection)
+	for _, button := range m.visibleButtons() {
+		button.Draw(shaderProgram, projection)
+	}
+
+	checkGLError("Menu.Draw")
+}
+
+func (m *Menu) Cleanup() {
+	gl.DeleteVertexArrays(1, &m.vao)
+	gl.DeleteBuffers(1, &m.vbo)
+	gl.DeleteTextures(1, &m.texture)
+	m.title.Cleanup()
+	for _, button := range m.buttons {
+		button.Cleanup()
+	}
+}
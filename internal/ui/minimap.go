@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"slices"
+
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Minimap is a top-down grid of loaded chunks in the top-right corner, each
+// colored by its most common surface block, with an arrow for the player.
+// North (-Z) is up. The grid is only rebuilt when the chunks change, the
+// arrow every frame.
+type Minimap struct {
+	gridVAO uint32
+	gridVBO uint32
+	markVAO uint32
+	markVBO uint32
+
+	screenWidth  int
+	screenHeight int
+	visible      bool
+
+	size   float32 // Side of the map, cells shrink to fit
+	margin float32
+
+	cells       []world.BlockType // What the grid was last built from
+	radius      int
+	gridDirty   bool
+	gridCount   int
+	vertices    []float32
+	markerCount int
+
+	texture uint32
+}
+
+func NewMinimap(screenWidth, screenHeight int) *Minimap {
+	return &Minimap{
+		screenWidth:  screenWidth,
+		screenHeight: screenHeight,
+		visible:      true,
+		size:         128.0,
+		margin:       10.0,
+	}
+}
+
+func (m *Minimap) Init() error {
+	gl.GenVertexArrays(1, &m.gridVAO)
+	gl.GenBuffers(1, &m.gridVBO)
+	gl.GenVertexArrays(1, &m.markVAO)
+	gl.GenBuffers(1, &m.markVBO)
+
+	// Create 1x1 White Texture
+	gl.GenTextures(1, &m.texture)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+	white := []uint8{255, 255, 255, 255}
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, 1, 1, 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(white))
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.NEAREST)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.NEAREST)
+
+	checkGLError("Minimap.Init")
+	return nil
+}
+
+func (m *Minimap) SetVisible(visible bool) {
+	m.visible = visible
+}
+
+func (m *Minimap) Update(state interface{}) {
+	if screenSize, ok := state.(*ScreenSize); ok {
+		m.screenWidth = screenSize.Width
+		m.screenHeight = screenSize.Height
+		m.gridDirty = true
+		return
+	}
+
+	mapState, ok := state.(MinimapState)
+	if !ok || !m.visible {
+		return
+	}
+	side := mapState.Radius*2 + 1
+	if len(mapState.Cells) < side*side {
+		return
+	}
+
+	if m.gridDirty || mapState.Radius != m.radius || !slices.Equal(mapState.Cells[:side*side], m.cells) {
+		m.radius = mapState.Radius
+		m.cells = append(m.cells[:0], mapState.Cells[:side*side]...)
+		m.generateGrid()
+		m.gridDirty = false
+	}
+	m.generateMarker(mapState)
+}
+
+// origin is the top-left corner of the map and the size of one cell
+func (m *Minimap) origin() (x, y, cellSize float32) {
+	cellSize = m.size / float32(m.radius*2+1)
+	return float32(m.screenWidth) - m.margin - m.size, m.margin, cellSize
+}
+
+func (m *Minimap) generateGrid() {
+	side := m.radius*2 + 1
+	startX, startY, cellSize := m.origin()
+
+	m.vertices = m.vertices[:0]
+	m.vertices = append(m.vertices, createFilledRect(startX-2, startY-2, m.size+4, m.size+4, mgl32.Vec3{0.05, 0.05, 0.05})...)
+
+	for row := 0; row < side; row++ {
+		for col := 0; col < side; col++ {
+			surface := m.cells[row*side+col]
+			if surface == world.BlockAir {
+				continue
+			}
+			x := startX + float32(col)*cellSize
+			y := startY + float32(row)*cellSize
+			m.vertices = append(m.vertices, createFilledRect(x, y, cellSize, cellSize, world.BlockColor(surface))...)
+		}
+	}
+
+	m.gridCount = len(m.vertices) / 7
+	uploadMinimapVertices(m.gridVAO, m.gridVBO, m.vertices)
+	checkGLError("Minimap.generateGrid")
+}
+
+// generateMarker builds the player arrow: a triangle pointing the way the
+// camera faces, flattened onto the map
+func (m *Minimap) generateMarker(state MinimapState) {
+	startX, startY, cellSize := m.origin()
+	center := mgl32.Vec2{
+		startX + (float32(state.Radius)+state.OffsetX)*cellSize,
+		startY + (float32(state.Radius)+state.OffsetZ)*cellSize,
+	}
+
+	// Screen Y runs down the map like world Z does
+	dir := mgl32.Vec2{state.Facing.X(), state.Facing.Z()}
+	if dir.Len() < 0.001 {
+		dir = mgl32.Vec2{0, -1} // Looking straight up or down
+	}
+	dir = dir.Normalize()
+	side := mgl32.Vec2{-dir.Y(), dir.X()}
+
+	tip := center.Add(dir.Mul(6))
+	left := center.Sub(dir.Mul(4)).Add(side.Mul(4))
+	right := center.Sub(dir.Mul(4)).Sub(side.Mul(4))
+
+	color := mgl32.Vec3{1, 1, 1}
+	vertices := make([]float32, 0, 21)
+	for _, p := range []mgl32.Vec2{tip, left, right} {
+		vertices = append(vertices, p.X(), p.Y(), color[0], color[1], color[2], 0.0, 0.0)
+	}
+
+	m.markerCount = len(vertices) / 7
+	uploadMinimapVertices(m.markVAO, m.markVBO, vertices)
+	checkGLError("Minimap.generateMarker")
+}
+
+func uploadMinimapVertices(vao, vbo uint32, vertices []float32) {
+	stride := int32(7 * 4)
+
+	gl.BindVertexArray(vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	// Position attribute (2D)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, stride, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+
+	// Color attribute
+	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+
+	//Texture Coord
+	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+}
+
+func (m *Minimap) Draw(shaderProgram uint32, projection mgl32.Mat4) {
+	if !m.visible || m.gridCount == 0 {
+		return
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, m.texture)
+
+	gl.BindVertexArray(m.gridVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(m.gridCount))
+	gl.BindVertexArray(m.markVAO)
+	gl.DrawArrays(gl.TRIANGLES, 0, int32(m.markerCount))
+	gl.BindVertexArray(0)
+
+	checkGLError("Minimap.Draw")
+}
+
+func (m *Minimap) Cleanup() {
+	gl.DeleteVertexArrays(1, &m.gridVAO)
+	gl.DeleteBuffers(1, &m.gridVBO)
+	gl.DeleteVertexArrays(1, &m.markVAO)
+	gl.DeleteBuffers(1, &m.markVBO)
+	gl.DeleteTextures(1, &m.texture)
+}
@@ -11,8 +11,24 @@ type Notification struct {
 	createdAt   time.Time
 	duration    time.Duration
 	textElement *Text
+	// Fade in and out rather than popping on and off
+	fade bool
 }
 
+// How long notifications take to fade in when added and out before they go
+const (
+	notificationFadeIn  = 300 * time.Millisecond
+	notificationFadeOut = 500 * time.Millisecond
+)
+
+// Defaults for Add, and the color for warnings and errors
+const DefaultNotificationDuration = 3 * time.Second
+
+var (
+	DefaultNotificationColor = mgl32.Vec3{1, 1, 1}
+	WarningNotificationColor = mgl32.Vec3{1, 0.35, 0.3}
+)
+
 type NotificationSystem struct {
 	font          *Font
 	notifications []Notification
@@ -64,7 +80,8 @@ func (ns *NotificationSystem) Update(state interface{}) {
 	active := make([]Notification, 0)
 
 	for i := range ns.notifications {
-		if now.Sub(ns.notifications[i].createdAt) < ns.notifications[i].duration {
+		if age := now.Sub(ns.notifications[i].createdAt); age < ns.notifications[i].duration {
+			ns.notifications[i].updateAlpha(age)
 			active = append(active, ns.notifications[i])
 		} else {
 			if ns.notifications[i].textElement != nil {
@@ -125,17 +142,66 @@ func (ns *NotificationSystem) Cleanup() {
 	ns.notifications = nil
 }
 
+// updateAlpha fades the text in at the start and out at the end of the
+// notification's duration
+func (n *Notification) updateAlpha(age time.Duration) {
+	if !n.fade {
+		return
+	}
+	alpha := float32(1)
+	if age < notificationFadeIn {
+		alpha = float32(age) / float32(notificationFadeIn)
+	}
+	if remaining := n.duration - age; remaining < notificationFadeOut {
+		alpha = min(alpha, float32(remaining)/float32(notificationFadeOut))
+	}
+	n.textElement.SetAlpha(alpha)
+}
+
+// NotificationOptions changes how AddWithOptions shows a message. Zero
+// fields keep the defaults Add uses.
+type NotificationOptions struct {
+	Duration time.Duration // Fades included, DefaultNotificationDuration if zero
+	Color    mgl32.Vec3    // DefaultNotificationColor if zero
+	// Show at full strength from the first frame to the last, for messages
+	// that shouldn't be missed
+	NoFade bool
+}
+
+// Add shows a white message that fades in, stays for a few seconds and
+// fades out
 func (ns *NotificationSystem) Add(message string) {
+	ns.AddWithOptions(message, NotificationOptions{})
+}
+
+// AddWithOptions shows a message with a different duration, color or
+// without fading. E.g. red for warnings that should linger, or short
+// confirmations.
+func (ns *NotificationSystem) AddWithOptions(message string, opts NotificationOptions) {
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultNotificationDuration
+	}
+	if opts.Color == (mgl32.Vec3{}) {
+		opts.Color = DefaultNotificationColor
+	}
+	ns.add(message, opts.Duration, opts.Color, !opts.NoFade)
+}
+
+func (ns *NotificationSystem) add(message string, duration time.Duration, color mgl32.Vec3, fade bool) {
 	// Calculate current scale
 	scaleFactor := float32(ns.screenWidth) / 1920.0
 	currentScale := ns.baseScale * scaleFactor
-	txt := NewText(ns.font, message, ns.xPos, ns.yPos, currentScale, mgl32.Vec3{1, 1, 1})
+	txt := NewText(ns.font, message, ns.xPos, ns.yPos, currentScale, color)
 	txt.Init() // Create VBOs
 
 	ns.notifications = append(ns.notifications, Notification{
 		content:     message,
 		createdAt:   time.Now(),
-		duration:    3 * time.Second,
+		duration:    duration,
 		textElement: txt,
+		fade:        fade,
 	})
+	if fade {
+		txt.SetAlpha(0)
+	}
 }
@@ -6,8 +6,14 @@ in vec2 TexCoord;
 out vec4 color;
 
 uniform sampler2D uTexture;
+uniform float uAlpha; // Element opacity, elements that fade set it and put it back to 1
+uniform bool uText;   // Font atlases only have coverage in red, text sets it while drawing
 
 void main() {
     vec4 sampled = texture(uTexture, TexCoord);
-    color = vec4(FragColor, 1.0) * sampled;
+    if (uText) {
+        color = vec4(FragColor, uAlpha * sampled.r);
+    } else {
+        color = vec4(FragColor, uAlpha) * sampled;
+    }
 }
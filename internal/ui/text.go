@@ -1,10 +1,23 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
 
+// Alignment is which point of each line Text's x position is
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// Text draws a string with its first baseline at y. Newlines start a new
+// line font.LineHeight further down, each aligned on x by itself.
 type Text struct {
 	font    *Font
 	content string
@@ -12,6 +25,8 @@ type Text struct {
 	x, y  float32
 	scale float32
 	color mgl32.Vec3
+	align Alignment
+	alpha float32 // Opacity, 1 is solid
 
 	vao         uint32
 	vbo         uint32
@@ -27,6 +42,7 @@ func NewText(font *Font, content string, x, y float32, scale float32, color mgl3
 		y:           y,
 		scale:       scale,
 		color:       color,
+		alpha:       1,
 		needsUpdate: true,
 	}
 }
@@ -69,16 +85,40 @@ func (t *Text) generateGeometry() {
 	}
 
 	vertices := make([]float32, 0)
-	cursorX := t.x
+	for i, line := range strings.Split(t.content, "\n") {
+		baseline := t.y + float32(i)*t.font.LineHeight*t.scale
+		vertices = t.appendLine(vertices, line, t.lineStart(line), baseline)
+	}
+
+	t.vertexCount = int32(len(vertices) / 7)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
+
+	t.needsUpdate = false
+}
+
+// lineStart is where a line begins for the alignment
+func (t *Text) lineStart(line string) float32 {
+	switch t.align {
+	case AlignCenter:
+		return t.x - t.font.MeasureString(line, t.scale)/2
+	case AlignRight:
+		return t.x - t.font.MeasureString(line, t.scale)
+	default:
+		return t.x
+	}
+}
 
-	for _, ch := range t.content {
-		glyph, ok := t.font.Glyphs[ch]
-		if !ok {
-			continue // Skip unknown characters
-		}
+// appendLine adds the quads of one line of text starting at x on the
+// baseline y
+func (t *Text) appendLine(vertices []float32, line string, x, y float32) []float32 {
+	cursorX := x
+	for _, ch := range line {
+		glyph := t.font.Glyph(ch)
 
 		xpos := cursorX + glyph.Bearing.X()*t.scale
-		ypos := t.y + (glyph.Bearing.Y()-glyph.Size.Y())*t.scale
+		ypos := y + (glyph.Bearing.Y()-glyph.Size.Y())*t.scale
 		w := glyph.Size.X() * t.scale
 		h := glyph.Size.Y() * t.scale
 
@@ -99,13 +139,7 @@ func (t *Text) generateGeometry() {
 		// Move cursor for next character
 		cursorX += glyph.Advance * t.scale
 	}
-
-	t.vertexCount = int32(len(vertices) / 7)
-
-	gl.BindBuffer(gl.ARRAY_BUFFER, t.vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.DYNAMIC_DRAW)
-
-	t.needsUpdate = false
+	return vertices
 }
 
 func (t *Text) SetContent(content string) {
@@ -115,6 +149,20 @@ func (t *Text) SetContent(content string) {
 	}
 }
 
+// SetAlpha sets the opacity from 0 (invisible) to 1 (solid)
+func (t *Text) SetAlpha(alpha float32) {
+	t.alpha = max(0, min(1, alpha))
+}
+
+// SetAlignment makes x the left edge, center or right edge of each line.
+// Text is left aligned by default.
+func (t *Text) SetAlignment(align Alignment) {
+	if t.align != align {
+		t.align = align
+		t.needsUpdate = true
+	}
+}
+
 func (t *Text) Update(state interface{}) {
 	if t.needsUpdate {
 		t.generateGeometry()
@@ -122,7 +170,7 @@ func (t *Text) Update(state interface{}) {
 }
 
 func (t *Text) Draw(shaderProgram uint32, projection mgl32.Mat4) {
-	if t.vertexCount <= 0 {
+	if t.vertexCount <= 0 || t.alpha <= 0 {
 		return
 	}
 
@@ -131,9 +179,17 @@ func (t *Text) Draw(shaderProgram uint32, projection mgl32.Mat4) {
 	gl.BindTexture(gl.TEXTURE_2D, t.font.TextureID)
 
 	// Draw Text
+	setTextMode(shaderProgram, true)
+	if t.alpha < 1 {
+		setAlpha(shaderProgram, t.alpha)
+	}
 	gl.BindVertexArray(t.vao)
 	gl.DrawArrays(gl.TRIANGLES, 0, t.vertexCount)
 	gl.BindVertexArray(0)
+	if t.alpha < 1 {
+		setAlpha(shaderProgram, 1)
+	}
+	setTextMode(shaderProgram, false)
 }
 
 func (t *Text) Cleanup() {
@@ -1,7 +1,69 @@
 package ui
 
+import (
+	"voxel-game/internal/world"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
 // ScreenSize represents window dimensions for UI updates
 type ScreenSize struct {
 	Width  int
 	Height int
 }
+
+// HotbarState is the hotbar contents and selection pushed from the game loop.
+// Empty slots are BlockAir. Counts holds the stack size of each slot.
+type HotbarState struct {
+	Slots    []world.BlockType
+	Counts   []int
+	Selected int
+}
+
+// ChunkMapState is a square grid of chunk statuses centered on the player,
+// as returned by World.ChunkStatusGrid
+type ChunkMapState struct {
+	Radius int
+	Cells  []world.ChunkStatus
+}
+
+// MinimapState is a square grid of chunk surface blocks centered on the
+// player's chunk, as returned by World.ChunkSurfaceGrid, and where the player
+// is and faces. OffsetX and OffsetZ are the player's position within the
+// center chunk, 0 to 1.
+type MinimapState struct {
+	Radius  int
+	Cells   []world.BlockType
+	OffsetX float32
+	OffsetZ float32
+	Facing  mgl32.Vec3
+}
+
+// HealthState is the player's health for the HealthBar
+type HealthState struct {
+	Health    float32
+	MaxHealth float32
+}
+
+// DebugInfo is what the debug overlay shows besides the render stats,
+// gathered by the game loop each frame
+type DebugInfo struct {
+	FPS       float64
+	FrameTime float32 // Seconds
+	FrameMode string  // Frame limiter setting
+
+	Position       mgl32.Vec3
+	Facing         mgl32.Vec3
+	ChunkX, ChunkZ int
+	RenderDistance int
+	Biome          string
+
+	MemMB      uint64
+	Goroutines int
+	Particles  int // Live break particles
+
+	Target    string // Block under the crosshair, "none" if nothing
+	CullFaces bool
+	TimeOfDay string
+	Seed      int64
+}
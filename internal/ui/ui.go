@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"voxel-game/internal/errs"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 )
@@ -18,6 +20,17 @@ var uiFragmentShaderSource string
 // OpenGL error checking utility
 const DEBUG_GL_ERRORS = true
 
+// Debug prints font metrics and shader details while the UI loads. Off by
+// default so normal runs are quiet; errors are reported either way.
+var Debug = false
+
+// debugf prints a loading detail when Debug is on
+func debugf(format string, args ...any) {
+	if Debug {
+		fmt.Printf(format, args...)
+	}
+}
+
 // UIElement interface - all UI elements must implement this
 type UIElement interface {
 	Init() error
@@ -26,6 +39,15 @@ type UIElement interface {
 	Cleanup()
 }
 
+// Clickable is implemented by elements that take the mouse. Positions are
+// in UI coordinates. HandleCursor follows the cursor for hover effects,
+// HandleClick gets left button presses and releases and reports whether it
+// used them.
+type Clickable interface {
+	HandleCursor(x, y float32)
+	HandleClick(x, y float32, pressed bool) bool
+}
+
 // UIRenderer manages all UI elements and orchestrates rendering
 type UIRenderer struct {
 	shaderProgram uint32
@@ -45,13 +67,13 @@ func NewUIRenderer(width, height int) (*UIRenderer, error) {
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("[UI] Vertex shader compiled successfully:", vertexShader)
+	debugf("[UI] Vertex shader compiled successfully: %d\n", vertexShader)
 
 	fragmentShader, err := compileShader(uiFragmentShaderSource, gl.FRAGMENT_SHADER)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println("[UI] Fragment shader compiled successfully:", fragmentShader)
+	debugf("[UI] Fragment shader compiled successfully: %d\n", fragmentShader)
 
 	// Link shader program
 	program := gl.CreateProgram()
@@ -67,7 +89,7 @@ func NewUIRenderer(width, height int) (*UIRenderer, error) {
 		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
-		return nil, fmt.Errorf("failed to link UI shader program: %v", log)
+		return nil, fmt.Errorf("UI shader program: %w", &errs.ShaderCompileError{Stage: "link", Log: log})
 	}
 
 	// Generate 1x1 White Texture
@@ -116,6 +138,41 @@ func (r *UIRenderer) Resize(width, height int) {
 	r.projection = mgl32.Ortho(0, float32(width), float32(height), 0, -1, 1)
 }
 
+// toUI scales window coordinates to the UI's logical size. The window is
+// windowWidth by windowHeight in screen coordinates.
+func (r *UIRenderer) toUI(x, y float64, windowWidth, windowHeight int) (float32, float32) {
+	if windowWidth <= 0 || windowHeight <= 0 {
+		return 0, 0
+	}
+	return float32(x * float64(r.width) / float64(windowWidth)),
+		float32(y * float64(r.height) / float64(windowHeight))
+}
+
+// HandleCursor passes the cursor position, in window coordinates, to every
+// Clickable element
+func (r *UIRenderer) HandleCursor(x, y float64, windowWidth, windowHeight int) {
+	uiX, uiY := r.toUI(x, y, windowWidth, windowHeight)
+	for _, element := range r.elements {
+		if clickable, ok := element.(Clickable); ok {
+			clickable.HandleCursor(uiX, uiY)
+		}
+	}
+}
+
+// HandleClick routes a left button press or release at window coordinates
+// to the topmost Clickable element that takes it
+func (r *UIRenderer) HandleClick(x, y float64, pressed bool, windowWidth, windowHeight int) bool {
+	uiX, uiY := r.toUI(x, y, windowWidth, windowHeight)
+
+	// Elements drawn last are on top, so they get first refusal
+	for i := len(r.elements) - 1; i >= 0; i-- {
+		if clickable, ok := r.elements[i].(Clickable); ok && clickable.HandleClick(uiX, uiY, pressed) {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *UIRenderer) Render() {
 	// Clear any pending errors from previous rendering
 	checkGLError("UIRenderer.Render start (clearing errors)")
@@ -152,6 +209,10 @@ func (r *UIRenderer) Render() {
 	loc := gl.GetUniformLocation(r.shaderProgram, gl.Str("uTexture\x00"))
 	gl.Uniform1i(loc, 0)
 
+	// Fully opaque unless an element fades itself
+	setAlpha(r.shaderProgram, 1)
+	setTextMode(r.shaderProgram, false)
+
 	// Draw all elements in order (determines layering)
 	for _, element := range r.elements {
 		element.Draw(r.shaderProgram, r.projection)
@@ -173,6 +234,7 @@ func (r *UIRenderer) Cleanup() {
 	for _, element := range r.elements {
 		element.Cleanup()
 	}
+	gl.DeleteTextures(1, &r.whiteTexture)
 	gl.DeleteProgram(r.shaderProgram)
 }
 
@@ -193,12 +255,33 @@ func compileShader(source string, shaderType uint32) (uint32, error) {
 		log := strings.Repeat("\x00", int(logLength+1))
 		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
 
-		return 0, fmt.Errorf("failed to compile UI shader: %v", log)
+		stage := "fragment"
+		if shaderType == gl.VERTEX_SHADER {
+			stage = "vertex"
+		}
+		return 0, fmt.Errorf("UI shader: %w", &errs.ShaderCompileError{Stage: stage, Log: log})
 	}
 
 	return shader, nil
 }
 
+// setAlpha sets the opacity the UI shader draws with
+func setAlpha(shaderProgram uint32, alpha float32) {
+	loc := gl.GetUniformLocation(shaderProgram, gl.Str("uAlpha\x00"))
+	gl.Uniform1f(loc, alpha)
+}
+
+// setTextMode switches the UI shader to reading a font atlas, whose red
+// channel is the glyph coverage
+func setTextMode(shaderProgram uint32, text bool) {
+	loc := gl.GetUniformLocation(shaderProgram, gl.Str("uText\x00"))
+	if text {
+		gl.Uniform1i(loc, 1)
+	} else {
+		gl.Uniform1i(loc, 0)
+	}
+}
+
 // Shared helper function for creating filled rectangles
 func createFilledRect(x, y, width, height float32, color mgl32.Vec3) []float32 {
 	return []float32{
@@ -241,27 +324,10 @@ func checkGLError(location string) {
 
 	// Debug mode - print errors
 	for {
-		err := gl.GetError()
-		if err == gl.NO_ERROR {
+		code := gl.GetError()
+		if code == gl.NO_ERROR {
 			break
 		}
-		fmt.Printf("[OpenGL Error] %s: %d (%s)\n", location, err, glErrorString(err))
-	}
-}
-
-func glErrorString(err uint32) string {
-	switch err {
-	case gl.INVALID_ENUM:
-		return "INVALID_ENUM"
-	case gl.INVALID_VALUE:
-		return "INVALID_VALUE"
-	case gl.INVALID_OPERATION:
-		return "INVALID_OPERATION"
-	case gl.INVALID_FRAMEBUFFER_OPERATION:
-		return "INVALID_FRAMEBUFFER_OPERATION"
-	case gl.OUT_OF_MEMORY:
-		return "OUT_OF_MEMORY"
-	default:
-		return fmt.Sprintf("UNKNOWN_ERROR_%d", err)
+		fmt.Printf("[OpenGL Error] %v\n", &errs.GLError{Op: location, Code: code})
 	}
 }
@@ -0,0 +1,108 @@
+package world
+
+// Biome is the kind of land a column belongs to. It picks the surface
+// blocks and how many trees grow.
+type Biome uint8
+
+const (
+	BiomePlains Biome = iota
+	BiomeDesert
+	BiomeForest
+	BiomeMountains
+	BiomeSnowy
+)
+
+func (b Biome) String() string {
+	switch b {
+	case BiomeDesert:
+		return "Desert"
+	case BiomeForest:
+		return "Forest"
+	case BiomeMountains:
+		return "Mountains"
+	case BiomeSnowy:
+		return "Snowy"
+	default:
+		return "Plains"
+	}
+}
+
+// biomeDef is what generation does differently in a biome
+type biomeDef struct {
+	top    BlockType // Surface block
+	filler BlockType // The few blocks under the surface
+	// Chance per surface column of a tree, before the forest noise thins
+	// it into groves and clearings. Trees only grow on grass and snow.
+	treeChance float64
+}
+
+var biomeDefs = [...]biomeDef{
+	BiomePlains:    {top: BlockGrass, filler: BlockDirt, treeChance: 0.002},
+	BiomeDesert:    {top: BlockSand, filler: BlockSand},
+	BiomeForest:    {top: BlockGrass, filler: BlockDirt, treeChance: 0.03},
+	BiomeMountains: {top: BlockGrass, filler: BlockDirt, treeChance: 0.004},
+	BiomeSnowy:     {top: BlockSnow, filler: BlockDirt, treeChance: 0.006},
+}
+
+const (
+	// Climate noise frequency, low so biomes span several chunks
+	climateFrequency = 0.0015
+
+	// Ruggedness above which a column counts as mountains, well into the
+	// tallest amplitude band in generateChunk
+	mountainRuggedness = 0.7
+
+	// Normalized climate cutoffs. Noise clusters around 0.5, so these sit
+	// fairly close to it.
+	snowyBelowTemperature = 0.38
+	desertAboveTemp       = 0.6
+	desertBelowMoisture   = 0.5
+	forestAboveMoisture   = 0.56
+
+	// Width of the cross-fade at biome borders, in noise units either side
+	// of each cutoff. Columns in the band pick a side at random, weighted by
+	// how far across they are, so palettes blend over a few blocks where the
+	// climate changes quickly and over more where it drifts slowly.
+	climateBlend    = 0.008
+	ruggednessBlend = 0.02
+)
+
+// BiomeAt returns the biome of the column at world position x, z
+func (w *World) BiomeAt(x, z int) Biome {
+	worldX, worldZ := float64(x), float64(z)
+	return w.biome(w.noise.Eval2(worldX*0.004, worldZ*0.004), worldX, worldZ, 0)
+}
+
+// biome classifies a column from the terrain's ruggedness and a
+// temperature and moisture climate sampled at worldX, worldZ. dither, from
+// -1 to 1, shifts every cutoff across its blend band.
+func (w *World) biome(ruggedness, worldX, worldZ, dither float64) Biome {
+	if ruggedness > mountainRuggedness+dither*ruggednessBlend {
+		return BiomeMountains
+	}
+
+	// Offset fields so temperature and moisture are uncorrelated with each
+	// other and with the terrain
+	temperature := w.noise.Eval2(worldX*climateFrequency+3000, worldZ*climateFrequency-3000)
+	moisture := w.noise.Eval2(worldX*climateFrequency-7000, worldZ*climateFrequency+7000)
+	shift := dither * climateBlend
+
+	switch {
+	case temperature < snowyBelowTemperature+shift:
+		return BiomeSnowy
+	case temperature > desertAboveTemp+shift && moisture < desertBelowMoisture+shift:
+		return BiomeDesert
+	case moisture > forestAboveMoisture+shift:
+		return BiomeForest
+	default:
+		return BiomePlains
+	}
+}
+
+// blendedBiome is the biome whose blocks and trees a column gets. Away from
+// borders that is BiomeAt, near one the two sides mix column by column.
+func (w *World) blendedBiome(x, z int, ruggedness float64) Biome {
+	// 16 hash bits clear of the ones the tree pass uses
+	dither := float64(w.positionHash(x, z)>>48)/0x8000 - 1
+	return w.biome(ruggedness, float64(x), float64(z), dither)
+}
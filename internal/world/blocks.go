@@ -2,17 +2,112 @@ package world
 
 type BlockType uint8
 
-// Block Types
+// Block Types. Values are stored in chunk data and save files, so never
+// renumber existing ones.
 const (
-	BlockAir   = 0
-	BlockDirt  = 1
-	BlockGrass = 2
-	BlockStone = 3
-	BlockSnow  = 4
-	BlockSand  = 5
-	BlockWood  = 6
+	BlockAir       BlockType = 0
+	BlockDirt      BlockType = 1
+	BlockGrass     BlockType = 2
+	BlockStone     BlockType = 3
+	BlockSnow      BlockType = 4
+	BlockSand      BlockType = 5
+	BlockWood      BlockType = 6
+	BlockLamp      BlockType = 7
+	BlockWater     BlockType = 8
+	BlockLog       BlockType = 9
+	BlockLeaves    BlockType = 10
+	BlockCoalOre   BlockType = 11
+	BlockIronOre   BlockType = 12
+	BlockGlowstone BlockType = 13
 )
 
+// Axis is the direction an Orientable block runs, e.g. a log's grain
+type Axis uint8
+
+const (
+	AxisY Axis = iota // Upright, the default
+	AxisX
+	AxisZ
+)
+
+// Block.State layout: the registry state index below stateAxisShift, the
+// axis above it
+const (
+	stateAxisShift   = 6
+	stateVariantMask = 1<<stateAxisShift - 1
+)
+
+func packState(variant uint8, axis Axis) uint8 {
+	return variant&stateVariantMask | uint8(axis)<<stateAxisShift
+}
+
+// Variant is the block's index into its registry States
+func (b Block) Variant() uint8 {
+	return b.State & stateVariantMask
+}
+
+// Axis is the way an Orientable block runs, AxisY for anything else
+func (b Block) Axis() Axis {
+	return Axis(b.State >> stateAxisShift)
+}
+
+// coord is the position component the axis runs along: 0 x, 1 y, 2 z
+func (a Axis) coord() int {
+	switch a {
+	case AxisX:
+		return 0
+	case AxisZ:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// AxisOfFace is the axis running through a face, towards the block it
+// was placed against
+func AxisOfFace(face int) Axis {
+	switch face {
+	case 0, 1:
+		return AxisZ
+	case 2, 3:
+		return AxisX
+	default:
+		return AxisY
+	}
+}
+
+// orientedFace turns a world face into the face of the upright block it
+// shows, e.g. the east face of a log lying along X is its end grain top
+func orientedFace(face int, axis Axis) int {
+	swap := func(end, side int) int {
+		switch face {
+		case end:
+			return 4
+		case end + 1:
+			return 5
+		case 4:
+			return side
+		case 5:
+			return side + 1
+		}
+		return face
+	}
+	switch axis {
+	case AxisX:
+		return swap(2, 2)
+	case AxisZ:
+		return swap(0, 0)
+	default:
+		return face
+	}
+}
+
+// grainAlongU reports whether a face's texture must turn a quarter so the
+// grain of an Orientable block follows its axis across the face
+func grainAlongU(block Block, face int) bool {
+	return GetBlockDef(block.Type).Orientable && faceUAxis[face] == block.Axis().coord()
+}
+
 // Texture Atlas Constants
 const (
 	TextureWidth  = 1152.0
@@ -29,39 +124,82 @@ var (
 	TexSnow      = [2]float32{3, 5}
 	TexSand      = [2]float32{3, 6}
 	TexWood      = [2]float32{0, 1}
+	TexLampOff   = [2]float32{0, 7}
+	TexLampOn    = [2]float32{8, 2}
+	TexWater     = [2]float32{7, 9}
+	TexLogSide   = [2]float32{1, 0}
+	TexLogTop    = [2]float32{0, 9}
+	TexLeaves    = [2]float32{4, 8}
+	TexCoalOre   = [2]float32{3, 0}
+	TexIronOre   = [2]float32{3, 2}
+	TexGlowstone = [2]float32{5, 2}
 )
 
-// Texture Coordinates helper
-func GetBlockUVs(blockType BlockType, faceDirection int) (float32, float32) {
-	var tileCoords [2]float32
+// BlockTile returns the atlas tile (column, row) used by one face of a block.
+// Faces: 0 front, 1 back, 2 right, 3 left, 4 top, 5 bottom. Orientable
+// blocks lying on their side show their top and bottom tiles on the faces
+// their axis runs through.
+func BlockTile(blockType BlockType, state uint8, faceDirection int) [2]float32 {
+	def := GetBlockDef(blockType)
+	// Multi-state blocks pick their tile from the registry
+	variant := state & stateVariantMask
+	if int(variant) < len(def.States) {
+		return def.States[variant].Texture
+	}
+	if def.Orientable {
+		faceDirection = orientedFace(faceDirection, Axis(state>>stateAxisShift))
+	}
 
 	switch blockType {
 	case BlockDirt:
-		tileCoords = TexDirt
+		return TexDirt
 	case BlockStone:
-		tileCoords = TexStone
+		return TexStone
 	case BlockSnow:
-		tileCoords = TexSnow
+		return TexSnow
 	case BlockSand:
-		tileCoords = TexSand
+		return TexSand
 	case BlockWood:
-		tileCoords = TexWood
+		return TexWood
+	case BlockWater:
+		return TexWater
+	case BlockLeaves:
+		return TexLeaves
+	case BlockCoalOre:
+		return TexCoalOre
+	case BlockIronOre:
+		return TexIronOre
+	case BlockGlowstone:
+		return TexGlowstone
+	case BlockLog:
+		if faceDirection == 4 || faceDirection == 5 { // End grain
+			return TexLogTop
+		}
+		return TexLogSide
 	case BlockGrass:
 		if faceDirection == 4 { // Top
-			tileCoords = TexGrassTop
+			return TexGrassTop
 		} else if faceDirection == 5 { // Bottom
-			tileCoords = TexDirt
-		} else {
-			tileCoords = TexGrassSide
+			return TexDirt
 		}
+		return TexGrassSide
 	default:
-		tileCoords = [2]float32{0, 0}
+		return [2]float32{0, 0}
 	}
+}
 
-	pixelX := (tileCoords[0] * TileSize)
-	pixelY := (tileCoords[1] * TileSize)
+// TileUVRect returns the UV rectangle covering an atlas tile. u0/v0 is the
+// tile's top-left corner in the image, u1/v1 the bottom-right.
+func TileUVRect(tile [2]float32) (u0, v0, u1, v1 float32) {
+	u0 = (tile[0] * TileSize) / TextureWidth
+	v0 = (tile[1] * TileSize) / TextureHeight
+	u1 = u0 + TileSize/TextureWidth
+	v1 = v0 + TileSize/TextureHeight
+	return u0, v0, u1, v1
+}
 
-	u := pixelX / TextureWidth
-	v := pixelY / TextureHeight
+// Texture Coordinates helper
+func GetBlockUVs(blockType BlockType, state uint8, faceDirection int) (float32, float32) {
+	u, v, _, _ := TileUVRect(BlockTile(blockType, state, faceDirection))
 	return u, v
 }
@@ -0,0 +1,60 @@
+package world
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTileUVRect(t *testing.T) {
+	const tileU, tileV = TileSize / TextureWidth, TileSize / TextureHeight
+
+	tests := []struct {
+		name  string
+		block BlockType
+		face  int
+		tile  [2]float32
+	}{
+		{"dirt", BlockDirt, 0, TexDirt},
+		{"grass top", BlockGrass, 4, TexGrassTop},
+		{"grass side", BlockGrass, 0, TexGrassSide},
+		{"grass bottom", BlockGrass, 5, TexDirt},
+		{"stone", BlockStone, 2, TexStone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tile := BlockTile(tt.block, 0, tt.face)
+			if tile != tt.tile {
+				t.Fatalf("BlockTile = %v, want %v", tile, tt.tile)
+			}
+
+			u0, v0, u1, v1 := TileUVRect(tile)
+			want := [4]float32{
+				tt.tile[0] * tileU, tt.tile[1] * tileV,
+				(tt.tile[0] + 1) * tileU, (tt.tile[1] + 1) * tileV,
+			}
+			for i, got := range [4]float32{u0, v0, u1, v1} {
+				if math.Abs(float64(got-want[i])) > 1e-6 {
+					t.Errorf("TileUVRect = %v, %v, %v, %v, want %v", u0, v0, u1, v1, want)
+					break
+				}
+			}
+			if math.Abs(float64(u1-u0-tileU)) > 1e-6 || math.Abs(float64(v1-v0-tileV)) > 1e-6 {
+				t.Errorf("tile spans %v x %v, want %v x %v", u1-u0, v1-v0, tileU, tileV)
+			}
+		})
+	}
+}
+
+func TestGrassFacesDiffer(t *testing.T) {
+	top := BlockTile(BlockGrass, 0, 4)
+	bottom := BlockTile(BlockGrass, 0, 5)
+	for face := 0; face < 4; face++ {
+		side := BlockTile(BlockGrass, 0, face)
+		if side == top || side == bottom {
+			t.Errorf("grass face %d uses the top or bottom tile %v", face, side)
+		}
+	}
+	if top == bottom {
+		t.Errorf("grass top and bottom both use %v", top)
+	}
+}
@@ -1,123 +1,373 @@
 package world
 
 import (
+	"sync"
+	"time"
+
 	"github.com/go-gl/gl/v4.1-core/gl"
 )
 
 type Chunk struct {
 	X, Z   int
-	Blocks [ChunkSize][ChunkHeight][ChunkSize]Block
+	blocks blockStore // Read and written through Get and Set, see palette.go
 	Mesh   *ChunkMesh
+	// Translucent faces drawn in a later blended pass, nil if the chunk has none
+	TransparentMesh *ChunkMesh
+
+	// Sky and block light of every cell, see lighting.go
+	SkyLight   [ChunkSize][ChunkHeight][ChunkSize]uint8
+	BlockLight [ChunkSize][ChunkHeight][ChunkSize]LightColor
+
+	// Set once generateMesh has run, even if the chunk produced no geometry
+	meshed bool
+	// Set once the player edits the chunk; edited chunks upload as DYNAMIC_DRAW
+	edited bool
+	// Blocks changed since the chunk was generated or last saved. Only
+	// dirty chunks are written, clean ones regenerate from the seed.
+	dirty bool
+
+	// Most common top block, cached for the minimap until the next edit
+	surface      BlockType
+	surfaceKnown bool
 }
 
 type ChunkMesh struct {
 	VAO         uint32
 	VBO         uint32
 	VertexCount int
+
+	capacity int // Size in bytes of the VBO's current data store
+
+	// When the renderer first drew this mesh, for the fade-in. The mesh
+	// survives remeshing, so edits don't fade the chunk in again.
+	FirstDrawn time.Time
+}
+
+// VertexFloats is the size of one chunk mesh vertex:
+// position (3), atlas tile origin (2), normal (3), tile-local UV (2), AO (1),
+// sky light (1), block light (3)
+const VertexFloats = 15
+
+// ChunkStatus is where a chunk is in the generate/mesh pipeline
+type ChunkStatus uint8
+
+const (
+	ChunkMissing   ChunkStatus = iota // Not loaded
+	ChunkQueued                       // Waiting on a generation worker
+	ChunkGenerated                    // Blocks generated, not meshed yet
+	ChunkMeshed                       // Meshed with geometry
+	ChunkEmpty                        // Meshed but produced no geometry
+	ChunkDirty                        // Meshed, with edits not saved yet
+)
+
+func (c *Chunk) Status() ChunkStatus {
+	switch {
+	case !c.meshed:
+		return ChunkGenerated
+	case c.dirty:
+		return ChunkDirty
+	case c.Mesh.empty() && c.TransparentMesh.empty():
+		return ChunkEmpty
+	default:
+		return ChunkMeshed
+	}
+}
+
+// Surface returns the most common topmost block across the chunk's columns,
+// for coloring maps. Air if the chunk is empty.
+func (c *Chunk) Surface() BlockType {
+	if c.surfaceKnown {
+		return c.surface
+	}
+
+	var counts [256]int
+	for x := 0; x < ChunkSize; x++ {
+		for z := 0; z < ChunkSize; z++ {
+			for y := ChunkHeight - 1; y >= 0; y-- {
+				if t := c.Get(x, y, z).Type; t != BlockAir {
+					counts[t]++
+					break
+				}
+			}
+		}
+	}
+
+	c.surface = BlockAir
+	for t, count := range counts {
+		if count > counts[c.surface] || (c.surface == BlockAir && count > 0) {
+			c.surface = BlockType(t)
+		}
+	}
+	c.surfaceKnown = true
+	return c.surface
+}
+
+// MeshUploadMode picks how remeshed vertex data is sent to the GPU
+type MeshUploadMode int
+
+const (
+	// UploadReplace calls BufferData with the new vertices directly
+	UploadReplace MeshUploadMode = iota
+	// UploadOrphan detaches the old data store first, so the driver can hand
+	// out fresh memory instead of waiting for draws still reading the old one
+	UploadOrphan
+)
+
+// empty reports whether there is nothing to draw. Safe on a nil mesh.
+func (m *ChunkMesh) empty() bool {
+	return m == nil || m.VertexCount == 0
+}
+
+// vertexPool recycles mesh vertex slices between rebuilds. Ownership: the
+// mesher takes slices from the pool, whoever consumes the meshVertices
+// calls release once it is done reading them, and nothing may keep a
+// reference after that. uploadMesh copies the data into GL, so
+// generateMesh can release as soon as it returns. A mesher running off
+// the GL thread must hand its result over and never touch it again.
+var vertexPool = sync.Pool{
+	New: func() any {
+		s := make([]float32, 0, 4096)
+		return &s
+	},
+}
+
+func getVertexSlice() []float32 {
+	return (*vertexPool.Get().(*[]float32))[:0]
+}
+
+func putVertexSlice(s []float32) {
+	if cap(s) == 0 {
+		return
+	}
+	s = s[:0]
+	vertexPool.Put(&s)
+}
+
+// meshVertices is the output of the mesher, split by render pass
+type meshVertices struct {
+	Opaque      []float32
+	Transparent []float32 // Stays nil for chunks without translucent blocks
+}
+
+// forBlock picks the slice a block's faces go into
+func (m *meshVertices) forBlock(blockType BlockType) *[]float32 {
+	if IsTranslucent(blockType) {
+		if m.Transparent == nil {
+			m.Transparent = getVertexSlice()
+		}
+		return &m.Transparent
+	}
+	return &m.Opaque
+}
+
+// release returns both slices to vertexPool. m must not be used afterwards.
+func (m *meshVertices) release() {
+	putVertexSlice(m.Opaque)
+	putVertexSlice(m.Transparent)
+	m.Opaque, m.Transparent = nil, nil
 }
 
+// generateMesh rebuilds the chunk's geometry and uploads it to the GPU
 func (c *Chunk) generateMesh(w *World) {
-	vertices := make([]float32, 0, 4096)
+	if w.headless {
+		c.meshed = true
+		return
+	}
+	vertices := c.buildVertices(w)
+	c.uploadMesh(vertices, w.meshUpload)
+	vertices.release()
+}
+
+// buildVertices produces the chunk's vertex data without touching OpenGL.
+// Neighbor chunks are read from w to cull faces along the edges. The slices
+// come from vertexPool, call release on the result when done with it.
+func (c *Chunk) buildVertices(w *World) meshVertices {
+	vertices := meshVertices{Opaque: getVertexSlice()}
 
 	// Cache neighbors to avoid map lookups in the inner loop
-	nLeft := w.chunks[[2]int{c.X - 1, c.Z}]
-	nRight := w.chunks[[2]int{c.X + 1, c.Z}]
-	nBack := w.chunks[[2]int{c.X, c.Z - 1}]
-	nFront := w.chunks[[2]int{c.X, c.Z + 1}]
+	nLeft := w.chunks[chunkKey(c.X-1, c.Z)]
+	nRight := w.chunks[chunkKey(c.X+1, c.Z)]
+	nBack := w.chunks[chunkKey(c.X, c.Z-1)]
+	nFront := w.chunks[chunkKey(c.X, c.Z+1)]
+
+	// Helper closure to find the chunk holding a cell, reaching into
+	// neighbor chunks. Returns nil for unloaded chunks.
+	cellAt := func(x, z int) (*Chunk, int, int) {
+		inX := x >= 0 && x < ChunkSize
+		inZ := z >= 0 && z < ChunkSize
+		switch {
+		case inX && inZ:
+			return c, x, z
+		case !inX && !inZ:
+			// Diagonal neighbor, only reached by ambient occlusion at the corners
+			cx, cz, lx, lz := worldToChunk(c.X*ChunkSize+x, c.Z*ChunkSize+z)
+			return w.chunks[chunkKey(cx, cz)], lx, lz
+		case x < 0:
+			return nLeft, ChunkSize - 1, z
+		case x >= ChunkSize:
+			return nRight, 0, z
+		case z < 0:
+			return nBack, x, ChunkSize - 1
+		default:
+			return nFront, x, 0
+		}
+	}
 
-	// Helper closure to check transparency
-	isTransparent := func(x, y, z int) bool {
+	// Out of range or unloaded cells read as air
+	blockAt := func(x, y, z int) BlockType {
 		if y < 0 || y >= ChunkHeight {
-			return true
+			return BlockAir
 		}
-		if x >= 0 && x < ChunkSize && z >= 0 && z < ChunkSize {
-			return c.Blocks[x][y][z].Type == BlockAir
+		chunk, lx, lz := cellAt(x, z)
+		if chunk == nil {
+			return BlockAir
 		}
-		// Neighbor checks
-		if x < 0 {
-			if nLeft == nil {
-				return true
-			}
-			return nLeft.Blocks[ChunkSize-1][y][z].Type == BlockAir
+		return chunk.Get(lx, y, lz).Type
+	}
+
+	// Light a face receives, from the cell it faces. Above the world is
+	// open sky; unloaded neighbors read as fully lit until they arrive.
+	faceLight := func(x, y, z, face int) vertexLight {
+		normal := faceNormals[face]
+		x, y, z = x+normal[0], y+normal[1], z+normal[2]
+		if y >= ChunkHeight {
+			return fullBright
 		}
-		if x >= ChunkSize {
-			if nRight == nil {
-				return true
-			}
-			return nRight.Blocks[0][y][z].Type == BlockAir
+		if y < 0 {
+			return newVertexLight(0, LightColor{})
 		}
-		if z < 0 {
-			if nBack == nil {
-				return true
-			}
-			return nBack.Blocks[x][y][ChunkSize-1].Type == BlockAir
+		chunk, lx, lz := cellAt(x, z)
+		if chunk == nil {
+			return fullBright
+		}
+		return newVertexLight(chunk.SkyLight[lx][y][lz], chunk.BlockLight[lx][y][lz])
+	}
+
+	// Helper closure to check transparency. Liquids show through to other
+	// blocks but not to themselves, so a body of water has no inner faces.
+	isTransparent := func(self BlockType, x, y, z int) bool {
+		neighbor := blockAt(x, y, z)
+		if neighbor == BlockAir {
+			return true
 		}
-		if z >= ChunkSize {
-			if nFront == nil {
-				return true
+		return IsLiquid(neighbor) && neighbor != self
+	}
+
+	// Ambient occlusion for the four corners of a face, from the two side
+	// blocks and the diagonal block next to each corner
+	occludes := func(x, y, z int) bool {
+		return IsSolid(blockAt(x, y, z))
+	}
+	faceAO := func(x, y, z, face int) [4]float32 {
+		normal := faceNormals[face]
+		adj := [3]int{x + normal[0], y + normal[1], z + normal[2]}
+		ua, va := faceUAxis[face], faceVAxis[face]
+
+		var ao [4]float32
+		for i, corner := range faceCorners[face] {
+			side1, side2, diag := adj, adj, adj
+			side1[ua] += corner[ua]*2 - 1
+			side2[va] += corner[va]*2 - 1
+			diag[ua], diag[va] = side1[ua], side2[va]
+
+			s1 := occludes(side1[0], side1[1], side1[2])
+			s2 := occludes(side2[0], side2[1], side2[2])
+			level := 3
+			if s1 && s2 {
+				level = 0
+			} else {
+				for _, o := range []bool{s1, s2, occludes(diag[0], diag[1], diag[2])} {
+					if o {
+						level--
+					}
+				}
 			}
-			return nFront.Blocks[x][y][0].Type == BlockAir
+			ao[i] = aoBrightness[level]
 		}
-		return true
+		return ao
+	}
+
+	if w.UseGreedyMeshing {
+		c.appendGreedyFaces(&vertices, isTransparent, faceAO, faceLight)
+		return vertices
 	}
 
 	for x := 0; x < ChunkSize; x++ {
 		for y := 0; y < ChunkHeight; y++ {
 			for z := 0; z < ChunkSize; z++ {
-				blockType := c.Blocks[x][y][z].Type
-				if blockType == BlockAir {
+				block := c.Get(x, y, z)
+				if block.Type == BlockAir {
 					continue
 				}
 
+				out := vertices.forBlock(block.Type)
 				wx := float32(c.X*ChunkSize + x)
 				wy := float32(y)
 				wz := float32(c.Z*ChunkSize + z)
 
 				// Face checks
-				if isTransparent(x, y, z+1) {
-					addFace(&vertices, wx, wy, wz, 0, blockType) // Front
+				if isTransparent(block.Type, x, y, z+1) {
+					addFace(out, wx, wy, wz, 0, block, faceAO(x, y, z, 0), faceLight(x, y, z, 0)) // Front
 				}
-				if isTransparent(x, y, z-1) {
-					addFace(&vertices, wx, wy, wz, 1, blockType) // Back
+				if isTransparent(block.Type, x, y, z-1) {
+					addFace(out, wx, wy, wz, 1, block, faceAO(x, y, z, 1), faceLight(x, y, z, 1)) // Back
 				}
-				if isTransparent(x+1, y, z) {
-					addFace(&vertices, wx, wy, wz, 2, blockType) // Right
+				if isTransparent(block.Type, x+1, y, z) {
+					addFace(out, wx, wy, wz, 2, block, faceAO(x, y, z, 2), faceLight(x, y, z, 2)) // Right
 				}
-				if isTransparent(x-1, y, z) {
-					addFace(&vertices, wx, wy, wz, 3, blockType) // Left
+				if isTransparent(block.Type, x-1, y, z) {
+					addFace(out, wx, wy, wz, 3, block, faceAO(x, y, z, 3), faceLight(x, y, z, 3)) // Left
 				}
-				if isTransparent(x, y+1, z) {
-					addFace(&vertices, wx, wy, wz, 4, blockType) // Top
+				if isTransparent(block.Type, x, y+1, z) {
+					addFace(out, wx, wy, wz, 4, block, faceAO(x, y, z, 4), faceLight(x, y, z, 4)) // Top
 				}
-				if isTransparent(x, y-1, z) {
-					addFace(&vertices, wx, wy, wz, 5, blockType) // Bottom
+				if isTransparent(block.Type, x, y-1, z) {
+					addFace(out, wx, wy, wz, 5, block, faceAO(x, y, z, 5), faceLight(x, y, z, 5)) // Bottom
 				}
 			}
 		}
 	}
 
+	return vertices
+}
+
+// uploadMesh sends both passes of vertex data to the GPU. GL thread only.
+func (c *Chunk) uploadMesh(vertices meshVertices, mode MeshUploadMode) {
+	c.meshed = true
+	c.Mesh = uploadVertices(c.Mesh, vertices.Opaque, mode, c.edited)
+	c.TransparentMesh = uploadVertices(c.TransparentMesh, vertices.Transparent, mode, c.edited)
+}
+
+// uploadVertices sends vertex data to a mesh's VAO/VBO, creating them on
+// first use. Returns the mesh, which is still nil if it never had geometry.
+func uploadVertices(m *ChunkMesh, vertices []float32, mode MeshUploadMode, edited bool) *ChunkMesh {
 	if len(vertices) == 0 {
-		return
+		// Keep the buffers for reuse but stop drawing the old geometry
+		if m != nil {
+			m.VertexCount = 0
+		}
+		return m
 	}
 
-	if c.Mesh == nil {
-		c.Mesh = &ChunkMesh{}
-		gl.GenVertexArrays(1, &c.Mesh.VAO)
-		gl.GenBuffers(1, &c.Mesh.VBO)
+	if m == nil {
+		m = &ChunkMesh{}
+		gl.GenVertexArrays(1, &m.VAO)
+		gl.GenBuffers(1, &m.VBO)
 	}
 
-	gl.BindVertexArray(c.Mesh.VAO)
-	gl.BindBuffer(gl.ARRAY_BUFFER, c.Mesh.VBO)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+	gl.BindVertexArray(m.VAO)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.VBO)
+	m.upload(vertices, mode, edited)
 
-	// Stride is 8 floats: X,Y,Z (3) + U,V (2) + Nx,Ny,Nz (3)
-	stride := int32(8 * 4)
+	// Stride is 15 floats: X,Y,Z (3) + U,V (2) + Nx,Ny,Nz (3) + LocalU,LocalV (2) + AO (1)
+	// + Sky (1) + BlockR,BlockG,BlockB (3)
+	stride := int32(VertexFloats * 4)
 
 	// Position (3 floats)
 	gl.EnableVertexAttribArray(0)
 	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, stride, gl.PtrOffset(0))
 
-	// TexCoord (2 floats) -- REPLACES COLOR
+	// Atlas tile origin (2 floats)
 	gl.EnableVertexAttribArray(1)
 	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, stride, gl.PtrOffset(3*4))
 
@@ -125,85 +375,139 @@ func (c *Chunk) generateMesh(w *World) {
 	gl.EnableVertexAttribArray(2)
 	gl.VertexAttribPointer(2, 3, gl.FLOAT, false, stride, gl.PtrOffset(5*4))
 
+	// Tile-local UV (2 floats), repeats the tile across merged quads
+	gl.EnableVertexAttribArray(3)
+	gl.VertexAttribPointer(3, 2, gl.FLOAT, false, stride, gl.PtrOffset(8*4))
+
+	// Ambient occlusion (1 float)
+	gl.EnableVertexAttribArray(4)
+	gl.VertexAttribPointer(4, 1, gl.FLOAT, false, stride, gl.PtrOffset(10*4))
+
+	// Sky light brightness (1 float)
+	gl.EnableVertexAttribArray(5)
+	gl.VertexAttribPointer(5, 1, gl.FLOAT, false, stride, gl.PtrOffset(11*4))
+
+	// Block light color (3 floats)
+	gl.EnableVertexAttribArray(6)
+	gl.VertexAttribPointer(6, 3, gl.FLOAT, false, stride, gl.PtrOffset(12*4))
+
 	gl.BindVertexArray(0)
-	c.Mesh.VertexCount = len(vertices) / 8 // 8 floats per vertex
-}
-
-func addFace(verts *[]float32, x, y, z float32, face int, bType BlockType) {
-	// Get UV coordinates for this specific face
-	u, v := GetBlockUVs(bType, face)
-
-	// Determine Normals based on face
-	var nx, ny, nz float32
-	switch face {
-	case 0:
-		nz = 1 // Front
-	case 1:
-		nz = -1 // Back
-	case 2:
-		nx = 1 // Right
-	case 3:
-		nx = -1 // Left
-	case 4:
-		ny = 1 // Top
-	case 5:
-		ny = -1 // Bottom
-	}
-
-	// Append Quad (2 Triangles)
-	// Format: X, Y, Z, U, V, Nx, Ny, Nz
-
-	// Helper to reduce typing
-	appendVert := func(vx, vy, vz, vu, vv float32) {
-		*verts = append(*verts, vx, vy, vz, vu, vv, nx, ny, nz)
-	}
-
-	uSize := float32(TileSize) / float32(TextureWidth)
-	vSize := float32(TileSize) / float32(TextureHeight) // Width of one tile in UV space
-
-	if face == 0 { // Front (+Z)
-		appendVert(x, y, z+1, u, v+vSize)         // Bottom Left
-		appendVert(x+1, y, z+1, u+uSize, v+vSize) // Bottom Right
-		appendVert(x+1, y+1, z+1, u+uSize, v)     // Top Right
-		appendVert(x, y, z+1, u, v+vSize)         // Bottom Left
-		appendVert(x+1, y+1, z+1, u+uSize, v)     // Top Right
-		appendVert(x, y+1, z+1, u, v)             // Top Left
-	} else if face == 1 { // Back (-Z)
-		// Note: UVs often need flipping depending on your specific atlas
-		// We use standard mapping here
-		appendVert(x+1, y, z, u, v+vSize)
-		appendVert(x, y, z, u+uSize, v+vSize)
-		appendVert(x, y+1, z, u+uSize, v) // Top Right
-		appendVert(x+1, y, z, u, v+vSize)
-		appendVert(x, y+1, z, u+uSize, v)
-		appendVert(x+1, y+1, z, u, v)
-	} else if face == 2 { // Right (+X)
-		appendVert(x+1, y, z+1, u, v+vSize)
-		appendVert(x+1, y, z, u+uSize, v+vSize)
-		appendVert(x+1, y+1, z, u+uSize, v) // Top Right
-		appendVert(x+1, y, z+1, u, v+vSize)
-		appendVert(x+1, y+1, z, u+uSize, v)
-		appendVert(x+1, y+1, z+1, u+uSize, v) // Top Right
-	} else if face == 3 { // Left (-X)
-		appendVert(x, y, z, u, v+vSize)
-		appendVert(x, y, z+1, u+uSize, v+vSize)
-		appendVert(x, y+1, z+1, u+uSize, v) // Top Right
-		appendVert(x, y, z, u, v+vSize)
-		appendVert(x, y+1, z+1, u+uSize, v)
-		appendVert(x, y+1, z, u, v)
-	} else if face == 4 { // Top (+Y)
-		appendVert(x, y+1, z+1, u, v+vSize)
-		appendVert(x+1, y+1, z+1, u+uSize, v+vSize)
-		appendVert(x+1, y+1, z, u+uSize, v)
-		appendVert(x, y+1, z+1, u, v+vSize)
-		appendVert(x+1, y+1, z, u+uSize, v)
-		appendVert(x, y+1, z, u, v)
-	} else if face == 5 { // Bottom (-Y)
-		appendVert(x, y, z, u, v+vSize)
-		appendVert(x+1, y, z, u+uSize, v+vSize)
-		appendVert(x+1, y, z+1, u+uSize, v)
-		appendVert(x, y, z, u, v+vSize)
-		appendVert(x+1, y, z+1, u+uSize, v)
-		appendVert(x, y, z+1, u, v)
+	m.VertexCount = len(vertices) / VertexFloats
+	return m
+}
+
+// deleteMeshes frees the chunk's GPU buffers when it is unloaded
+func (c *Chunk) deleteMeshes() {
+	for _, m := range []*ChunkMesh{c.Mesh, c.TransparentMesh} {
+		if m != nil {
+			gl.DeleteVertexArrays(1, &m.VAO)
+			gl.DeleteBuffers(1, &m.VBO)
+		}
+	}
+	c.Mesh, c.TransparentMesh = nil, nil
+}
+
+// upload sends vertices to the bound VBO. Chunks that are being edited get a
+// DYNAMIC_DRAW hint since they will likely be rewritten again soon.
+func (m *ChunkMesh) upload(vertices []float32, mode MeshUploadMode, edited bool) {
+	size := len(vertices) * 4
+	usage := uint32(gl.STATIC_DRAW)
+	if edited {
+		usage = gl.DYNAMIC_DRAW
+	}
+
+	if mode == UploadOrphan && m.capacity > 0 {
+		// Orphan: a nil BufferData releases the old store to the driver,
+		// then the new data is written into the fresh allocation
+		gl.BufferData(gl.ARRAY_BUFFER, size, nil, usage)
+		gl.BufferSubData(gl.ARRAY_BUFFER, 0, size, gl.Ptr(vertices))
+	} else {
+		gl.BufferData(gl.ARRAY_BUFFER, size, gl.Ptr(vertices), usage)
+	}
+	m.capacity = size
+}
+
+// AppendBlockCube appends all six faces of a block with its corner at x,y,z,
+// in the same vertex layout as chunk meshes
+func AppendBlockCube(verts []float32, x, y, z float32, block Block) []float32 {
+	for face := 0; face < 6; face++ {
+		addFace(&verts, x, y, z, face, block, NoOcclusion, fullBright)
+	}
+	return verts
+}
+
+func addFace(verts *[]float32, x, y, z float32, face int, block Block, ao [4]float32, light vertexLight) {
+	addQuad(verts, x, y, z, 1, 1, 1, face, block, ao, light)
+}
+
+// Unit-cube corners of each face in bottom-left, bottom-right, top-right,
+// top-left order as seen from outside the block
+var faceCorners = [6][4][3]int{
+	{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1}}, // Front (+Z)
+	{{1, 0, 0}, {0, 0, 0}, {0, 1, 0}, {1, 1, 0}}, // Back (-Z)
+	{{1, 0, 1}, {1, 0, 0}, {1, 1, 0}, {1, 1, 1}}, // Right (+X)
+	{{0, 0, 0}, {0, 0, 1}, {0, 1, 1}, {0, 1, 0}}, // Left (-X)
+	{{0, 1, 1}, {1, 1, 1}, {1, 1, 0}, {0, 1, 0}}, // Top (+Y)
+	{{0, 0, 0}, {1, 0, 0}, {1, 0, 1}, {0, 0, 1}}, // Bottom (-Y)
+}
+
+var faceNormals = [6][3]int{{0, 0, 1}, {0, 0, -1}, {1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}}
+
+// Axes the texture's U (left to right) and V (bottom to top) run along per face
+var (
+	faceUAxis = [6]int{0, 0, 2, 2, 0, 0}
+	faceVAxis = [6]int{1, 1, 1, 1, 2, 2}
+)
+
+// aoBrightness maps the number of unoccluded neighbors at a corner (0-3)
+// to a light multiplier
+var aoBrightness = [4]float32{0.5, 0.7, 0.85, 1.0}
+
+// NoOcclusion is the AO for a face with nothing around it
+var NoOcclusion = [4]float32{1, 1, 1, 1}
+
+// addQuad emits one face of a box with its corner at x,y,z and size
+// sx,sy,sz. The tile-local UVs run 0..size along the face so the shader can
+// repeat the block's tile across merged quads. ao holds the brightness of
+// each corner in faceCorners order and light is shared by the whole face.
+func addQuad(verts *[]float32, x, y, z, sx, sy, sz float32, face int, block Block, ao [4]float32, light vertexLight) {
+	// Get the atlas tile origin for this specific face
+	u, v, _, _ := TileUVRect(BlockTile(block.Type, block.State, face))
+
+	normal := faceNormals[face]
+	nx, ny, nz := float32(normal[0]), float32(normal[1]), float32(normal[2])
+
+	origin := [3]float32{x, y, z}
+	size := [3]float32{sx, sy, sz}
+	uSize := size[faceUAxis[face]]
+	vSize := size[faceVAxis[face]]
+	localUV := [4][2]float32{{0, vSize}, {uSize, vSize}, {uSize, 0}, {0, 0}}
+	if grainAlongU(block, face) {
+		// Texture turned a quarter, its V runs along the face's U
+		localUV = [4][2]float32{{0, 0}, {0, uSize}, {vSize, uSize}, {vSize, 0}}
+	}
+
+	// Format: X, Y, Z, U, V, Nx, Ny, Nz, LocalU, LocalV, AO, Sky, BlockR, BlockG, BlockB
+	appendCorner := func(i int) {
+		corner := faceCorners[face][i]
+		*verts = append(*verts,
+			origin[0]+float32(corner[0])*size[0],
+			origin[1]+float32(corner[1])*size[1],
+			origin[2]+float32(corner[2])*size[2],
+			u, v, nx, ny, nz, localUV[i][0], localUV[i][1], ao[i],
+			light.sky, light.block[0], light.block[1], light.block[2])
+	}
+
+	// Append Quad (2 Triangles). Split along the diagonal whose corners are
+	// brighter so the AO gradient interpolates evenly instead of leaving a
+	// dark seam across the quad.
+	if ao[0]+ao[2] >= ao[1]+ao[3] {
+		for _, i := range [6]int{0, 1, 2, 0, 2, 3} {
+			appendCorner(i)
+		}
+	} else {
+		for _, i := range [6]int{0, 1, 3, 1, 2, 3} {
+			appendCorner(i)
+		}
 	}
 }
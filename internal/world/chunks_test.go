@@ -0,0 +1,28 @@
+package world
+
+import "testing"
+
+// BenchmarkMeshRebuild rebuilds the vertices of one generated chunk, the CPU
+// side of generateMesh. For the 1000 rebuild comparison run it with
+// -benchtime=1000x.
+func BenchmarkMeshRebuild(b *testing.B) {
+	for _, greedy := range []bool{false, true} {
+		name := "naive"
+		if greedy {
+			name = "greedy"
+		}
+		b.Run(name, func(b *testing.B) {
+			w := newWorld(1)
+			w.UseGreedyMeshing = greedy
+			chunk := w.generateChunk(0, 0)
+			w.chunks[chunkKey(0, 0)] = chunk
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				vertices := chunk.buildVertices(w)
+				vertices.release()
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package world
+
+import "math"
+
+// worldToChunk splits a world block coordinate into its chunk coordinate and
+// the position inside that chunk. Both use floor semantics, so x = -1 is the
+// last column (15) of chunk -1 rather than column -1 of chunk 0.
+func worldToChunk(x, z int) (chunkX, chunkZ, localX, localZ int) {
+	return floorDiv(x, ChunkSize), floorDiv(z, ChunkSize), floorMod(x, ChunkSize), floorMod(z, ChunkSize)
+}
+
+// floorDiv divides rounding towards negative infinity. Go's / truncates
+// toward zero, which would put x = -1 in chunk 0.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// floorMod is the remainder matching floorDiv, always in [0, b) for b > 0
+func floorMod(a, b int) int {
+	m := a % b
+	if m != 0 && (m < 0) != (b < 0) {
+		m += b
+	}
+	return m
+}
+
+// chunkKey is the map key for the chunk at the given chunk coordinates
+func chunkKey(chunkX, chunkZ int) [2]int {
+	return [2]int{chunkX, chunkZ}
+}
+
+// ChunkOf returns the chunk coordinates containing a world position
+func ChunkOf(x, z float32) (chunkX, chunkZ int) {
+	chunkX, chunkZ, _, _ = worldToChunk(int(math.Floor(float64(x))), int(math.Floor(float64(z))))
+	return chunkX, chunkZ
+}
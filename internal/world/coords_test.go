@@ -0,0 +1,80 @@
+package world
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWorldToChunk(t *testing.T) {
+	tests := []struct {
+		x, z                           int
+		chunkX, chunkZ, localX, localZ int
+	}{
+		{0, 0, 0, 0, 0, 0},
+		{15, 15, 0, 0, 15, 15},
+		{16, 16, 1, 1, 0, 0},
+		{17, 31, 1, 1, 1, 15},
+		{32, 0, 2, 0, 0, 0},
+		{-1, -1, -1, -1, 15, 15},
+		{-15, -16, -1, -1, 1, 0},
+		{-16, -17, -1, -2, 0, 15},
+		{-32, -33, -2, -3, 0, 15},
+		{5, -1, 0, -1, 5, 15},
+	}
+	for _, tt := range tests {
+		chunkX, chunkZ, localX, localZ := worldToChunk(tt.x, tt.z)
+		if chunkX != tt.chunkX || chunkZ != tt.chunkZ || localX != tt.localX || localZ != tt.localZ {
+			t.Errorf("worldToChunk(%d, %d) = chunk %d, %d local %d, %d, want chunk %d, %d local %d, %d",
+				tt.x, tt.z, chunkX, chunkZ, localX, localZ, tt.chunkX, tt.chunkZ, tt.localX, tt.localZ)
+		}
+	}
+}
+
+func TestChunkOf(t *testing.T) {
+	tests := []struct {
+		x, z           float32
+		chunkX, chunkZ int
+	}{
+		{0, 0, 0, 0},
+		{0.5, 15.99, 0, 0},
+		{16, 16, 1, 1},
+		{-0.01, -0.5, -1, -1},
+		{-16, -16.01, -1, -2},
+		{-32.5, 31.5, -3, 1},
+	}
+	for _, tt := range tests {
+		chunkX, chunkZ := ChunkOf(tt.x, tt.z)
+		if chunkX != tt.chunkX || chunkZ != tt.chunkZ {
+			t.Errorf("ChunkOf(%v, %v) = %d, %d, want %d, %d", tt.x, tt.z, chunkX, chunkZ, tt.chunkX, tt.chunkZ)
+		}
+	}
+}
+
+func TestFloorDivMod(t *testing.T) {
+	for x := -33; x <= 33; x++ {
+		for _, b := range []int{1, 3, 16} {
+			wantDiv := int(math.Floor(float64(x) / float64(b)))
+			wantMod := x - wantDiv*b
+			if got := floorDiv(x, b); got != wantDiv {
+				t.Errorf("floorDiv(%d, %d) = %d, want %d", x, b, got, wantDiv)
+			}
+			if got := floorMod(x, b); got != wantMod {
+				t.Errorf("floorMod(%d, %d) = %d, want %d", x, b, got, wantMod)
+			}
+			if m := floorMod(x, b); m < 0 || m >= b {
+				t.Errorf("floorMod(%d, %d) = %d, outside [0, %d)", x, b, m, b)
+			}
+		}
+	}
+
+	// Spot checks around the chunk boundaries
+	tests := []struct{ x, div, mod int }{
+		{-33, -3, 15}, {-32, -2, 0}, {-17, -2, 15}, {-16, -1, 0}, {-1, -1, 15},
+		{0, 0, 0}, {15, 0, 15}, {16, 1, 0}, {31, 1, 15}, {32, 2, 0}, {33, 2, 1},
+	}
+	for _, tt := range tests {
+		if div, mod := floorDiv(tt.x, ChunkSize), floorMod(tt.x, ChunkSize); div != tt.div || mod != tt.mod {
+			t.Errorf("floorDiv, floorMod(%d, %d) = %d, %d, want %d, %d", tt.x, ChunkSize, div, mod, tt.div, tt.mod)
+		}
+	}
+}
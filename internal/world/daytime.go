@@ -0,0 +1,136 @@
+package world
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// DayLength is the real-time length of one full day in seconds
+const DayLength = 600.0
+
+// Named times of day, as fractions of a day starting at midnight
+const (
+	TimeMidnight = 0.0
+	TimeSunrise  = 0.25
+	TimeNoon     = 0.5
+	TimeSunset   = 0.75
+)
+
+// DayClock tracks the time of day as a fraction in [0, 1), 0 being midnight
+type DayClock struct {
+	TimeOfDay float32
+	Frozen    bool
+	Speed     float32 // Time multiplier, 1 means a day lasts DayLength seconds
+}
+
+func NewDayClock(timeOfDay float32) *DayClock {
+	c := &DayClock{Speed: 1}
+	c.Set(timeOfDay)
+	return c
+}
+
+// Advance moves time forward by dt seconds unless the clock is frozen
+func (c *DayClock) Advance(dt float32) {
+	if c.Frozen {
+		return
+	}
+	c.Set(c.TimeOfDay + dt*c.Speed/DayLength)
+}
+
+// Set jumps to a time of day, wrapping into [0, 1)
+func (c *DayClock) Set(timeOfDay float32) {
+	t := float32(math.Mod(float64(timeOfDay), 1))
+	if t < 0 {
+		t++
+	}
+	c.TimeOfDay = t
+}
+
+// SunDirection is the direction sunlight travels. The sun rises in the east
+// (+X), peaks at noon and sets in the west, tilted slightly south so noon
+// light isn't perfectly vertical.
+func (c *DayClock) SunDirection() mgl32.Vec3 {
+	angle := float64(c.TimeOfDay-TimeSunrise) * 2 * math.Pi
+	sunPos := mgl32.Vec3{
+		float32(math.Cos(angle)),
+		float32(math.Sin(angle)),
+		0.3,
+	}
+	return sunPos.Mul(-1).Normalize()
+}
+
+// sunHeight is the sine of the sun's elevation, -1 at midnight to 1 at noon
+func (c *DayClock) sunHeight() float64 {
+	return math.Sin(float64(c.TimeOfDay-TimeSunrise) * 2 * math.Pi)
+}
+
+// Daylight is 1 at noon, 0 at night, with a short blend around sunrise and sunset
+func (c *DayClock) Daylight() float32 {
+	return float32(math.Min(math.Max(c.sunHeight()*4+0.5, 0), 1))
+}
+
+// twilight peaks at 1 while the sun crosses the horizon and falls smoothly
+// to 0 once it is well above or below it
+func (c *DayClock) twilight() float32 {
+	t := 1 - math.Min(math.Abs(c.sunHeight())/0.3, 1)
+	return float32(t * t * (3 - 2*t))
+}
+
+// SkyColor blends between the night and day sky colors, warming towards
+// orange around sunrise and sunset
+func (c *DayClock) SkyColor() mgl32.Vec3 {
+	day := mgl32.Vec3{0.53, 0.81, 0.92}
+	night := mgl32.Vec3{0.02, 0.03, 0.08}
+	dusk := mgl32.Vec3{0.93, 0.52, 0.32}
+	sky := night.Add(day.Sub(night).Mul(c.Daylight()))
+	return sky.Add(dusk.Sub(sky).Mul(c.twilight() * 0.6))
+}
+
+// SkyGradient returns the sky color straight up and at the horizon. The
+// horizon is SkyColor so fog blends into it; the zenith is a deeper blue
+// that keeps less of the sunrise and sunset tint.
+func (c *DayClock) SkyGradient() (zenith, horizon mgl32.Vec3) {
+	day := mgl32.Vec3{0.25, 0.5, 0.85}
+	night := mgl32.Vec3{0.01, 0.01, 0.04}
+	dusk := mgl32.Vec3{0.35, 0.3, 0.5}
+	zenith = night.Add(day.Sub(night).Mul(c.Daylight()))
+	zenith = zenith.Add(dusk.Sub(zenith).Mul(c.twilight() * 0.4))
+	return zenith, c.SkyColor()
+}
+
+// SunGlowColor is the haze around the sun: orange while it is near the
+// horizon, faint yellow-white by day and nothing once it has set
+func (c *DayClock) SunGlowColor() mgl32.Vec3 {
+	day := mgl32.Vec3{0.25, 0.22, 0.15}
+	dusk := mgl32.Vec3{0.9, 0.45, 0.15}
+	glow := day.Add(dusk.Sub(day).Mul(c.twilight()))
+	visible := float32(math.Min(math.Max(c.sunHeight()*5+0.5, 0), 1))
+	return glow.Mul(visible)
+}
+
+// AmbientColor is the sunless light everything receives: neutral grey by
+// day, a dim blue at night
+func (c *DayClock) AmbientColor() mgl32.Vec3 {
+	day := mgl32.Vec3{0.3, 0.3, 0.3}
+	night := mgl32.Vec3{0.06, 0.07, 0.12}
+	return night.Add(day.Sub(night).Mul(c.Daylight()))
+}
+
+// String formats the time as a 24-hour HH:MM clock
+func (c *DayClock) String() string {
+	minutes := int(c.TimeOfDay * 24 * 60)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// NextPreset returns the next named time (sunrise, noon, sunset, midnight) after t
+func NextPreset(t float32) float32 {
+	presets := []float32{TimeSunrise, TimeNoon, TimeSunset}
+	for _, p := range presets {
+		if t < p-0.001 {
+			return p
+		}
+	}
+	return TimeMidnight
+}
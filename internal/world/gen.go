@@ -0,0 +1,180 @@
+package world
+
+import "math"
+
+// GenConfig holds the tunable terrain generation parameters
+type GenConfig struct {
+	Seed int64
+
+	// Fractal noise detail for the elevation and mountain layers.
+	// More octaves add finer detail at the cost of generation time.
+	ElevationOctaves int
+	MountainOctaves  int
+	Lacunarity       float64 // Frequency multiplier per octave
+	Persistence      float64 // Amplitude multiplier per octave
+
+	// Rivers are carved where a ridged noise channel is near zero.
+	// Widths are in ridge-noise units, not blocks.
+	Rivers         bool
+	RiverFrequency float64
+	RiverWidth     float64 // Flat water channel
+	RiverBankWidth float64 // Blend zone from the channel up to the terrain
+	RiverDepth     int     // Blocks below SeaLevel at the channel center
+}
+
+func DefaultGenConfig() GenConfig {
+	return GenConfig{
+		Seed:             12345,
+		ElevationOctaves: 3,
+		MountainOctaves:  2,
+		Lacunarity:       2.0,
+		Persistence:      0.5,
+
+		Rivers:         true,
+		RiverFrequency: 0.003,
+		RiverWidth:     0.03,
+		RiverBankWidth: 0.06,
+		RiverDepth:     3,
+	}
+}
+
+// fbm sums octaves of the world's noise (fractal Brownian motion).
+// The result is normalized by the total amplitude so it stays in the
+// same [0, 1] range as a single Eval2 call on normalized noise.
+func (w *World) fbm(x, z float64, octaves int, lacunarity, persistence float64) float64 {
+	if octaves < 1 {
+		octaves = 1
+	}
+
+	total := 0.0
+	amplitude := 1.0
+	frequency := 1.0
+	maxValue := 0.0
+
+	for i := 0; i < octaves; i++ {
+		total += w.noise.Eval2(x*frequency, z*frequency) * amplitude
+		maxValue += amplitude
+		amplitude *= persistence
+		frequency *= lacunarity
+	}
+
+	return total / maxValue
+}
+
+// mountainAmplitude is how tall mountains grow for a ruggedness value. It
+// rises smoothly from gentle plains (about 10 blocks) through hills (40 at
+// 0.6) to towering peaks (140), so there is no step where bands meet.
+func mountainAmplitude(ruggedness float64) float64 {
+	t := math.Max(0, math.Min((ruggedness-0.1)/0.9, 1))
+	return 10 + 130*math.Pow(t, 2.5)
+}
+
+// carveRiver lowers the terrain height along the river network. Channels
+// are cut below SeaLevel so the sea fill pass turns them into water.
+func (w *World) carveRiver(worldX, worldZ, height float64) float64 {
+	g := &w.gen
+
+	// Ridged noise: folding around the midpoint gives a value near zero
+	// along a continuous meandering line
+	n := w.fbm(worldX*g.RiverFrequency, worldZ*g.RiverFrequency, 2, g.Lacunarity, g.Persistence)
+	ridge := math.Abs(n*2 - 1)
+
+	bed := float64(SeaLevel - g.RiverDepth)
+	if height <= bed {
+		return height
+	}
+
+	// Taller terrain gets wider banks so the valley walls stay gentle
+	bank := g.RiverBankWidth * math.Max(1, (height-bed)/20)
+	if ridge >= g.RiverWidth+bank {
+		return height
+	}
+	if ridge <= g.RiverWidth {
+		return bed
+	}
+
+	// Smoothstep from the river bed up to the original terrain
+	t := (ridge - g.RiverWidth) / bank
+	t = t * t * (3 - 2*t)
+	return bed + (height-bed)*t
+}
+
+// carveCaves hollows out tunnels in one column of a freshly generated chunk.
+// The band narrows to nothing towards the surface, so the top layer (and the
+// sea or river bed) is never opened up and grass isn't left floating.
+func (w *World) carveCaves(chunk *Chunk, x, z, surface int) {
+	if w.CaveThreshold <= 0 {
+		return
+	}
+
+	worldX := float64(chunk.X*ChunkSize + x)
+	worldZ := float64(chunk.Z*ChunkSize + z)
+	f := w.CaveFrequency
+	fade := float64(max(w.CaveSurfaceDepth, 1))
+
+	// Leave the bottom layer solid
+	for y := 1; y < surface; y++ {
+		band := w.CaveThreshold * math.Min(float64(surface-y)/fade, 1)
+
+		worldY := float64(y)
+		a := w.noise.Eval3(worldX*f, worldY*f, worldZ*f)
+		if math.Abs(a-0.5) >= band {
+			continue
+		}
+		// Second field offset far away so the two are uncorrelated
+		b := w.noise.Eval3(worldX*f+1000, worldY*f, worldZ*f+1000)
+		if math.Abs(b-0.5) >= band {
+			continue
+		}
+		chunk.Set(x, y, z, Block{Type: BlockAir})
+	}
+}
+
+// OreVein describes where one ore replaces stone. Blobs form where a 3D noise
+// field rises above Threshold. The threshold climbs by up to RarityFalloff
+// from MinY to MaxY, so the ore thins out towards the top of its band.
+type OreVein struct {
+	Type          BlockType
+	Frequency     float64 // Higher gives smaller, more numerous blobs
+	Threshold     float64 // Normalized noise cutoff at MinY
+	RarityFalloff float64 // Added to Threshold at MaxY
+	MinY, MaxY    int
+}
+
+// DefaultOres returns the standard ore distribution: coal is common and
+// reaches fairly high, iron is scarcer and stays deep
+func DefaultOres() []OreVein {
+	return []OreVein{
+		{Type: BlockCoalOre, Frequency: 0.15, Threshold: 0.8, RarityFalloff: 0.04, MinY: 1, MaxY: 96},
+		{Type: BlockIronOre, Frequency: 0.18, Threshold: 0.82, RarityFalloff: 0.04, MinY: 1, MaxY: 40},
+	}
+}
+
+// oreMinDepth is how many blocks under the surface ore starts, so bare
+// mountain stone isn't speckled with it
+const oreMinDepth = 4
+
+// placeOres swaps stone for ore in one column of a freshly generated chunk.
+// Runs after caves are carved, so ore shows in cave walls.
+func (w *World) placeOres(chunk *Chunk, x, z, surface int) {
+	worldX := float64(chunk.X*ChunkSize + x)
+	worldZ := float64(chunk.Z*ChunkSize + z)
+
+	for i, ore := range w.Ores {
+		// Shift each ore's field so veins don't all line up
+		offset := float64(i+1) * 2000
+		top := min(ore.MaxY, surface-oreMinDepth)
+		span := float64(max(ore.MaxY-ore.MinY, 1))
+
+		for y := max(ore.MinY, 1); y <= top; y++ {
+			if chunk.Get(x, y, z).Type != BlockStone {
+				continue
+			}
+			threshold := ore.Threshold + ore.RarityFalloff*float64(y-ore.MinY)/span
+			n := w.noise.Eval3(worldX*ore.Frequency+offset, float64(y)*ore.Frequency, worldZ*ore.Frequency-offset)
+			if n > threshold {
+				chunk.Set(x, y, z, Block{Type: ore.Type})
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package world
+
+import "testing"
+
+func TestFbmDeterministic(t *testing.T) {
+	a, b := newWorld(42), newWorld(42)
+	other := newWorld(43)
+	differs := false
+	for x := -50.0; x <= 50; x += 12.5 {
+		for z := -50.0; z <= 50; z += 12.5 {
+			va := a.fbm(x*0.01, z*0.01, 4, 2, 0.5)
+			if vb := b.fbm(x*0.01, z*0.01, 4, 2, 0.5); va != vb {
+				t.Fatalf("seed 42 gave %v then %v at %v, %v", va, vb, x, z)
+			}
+			if other.fbm(x*0.01, z*0.01, 4, 2, 0.5) != va {
+				differs = true
+			}
+		}
+	}
+	if !differs {
+		t.Error("seeds 42 and 43 gave the same values everywhere")
+	}
+}
+
+func TestFbmRange(t *testing.T) {
+	w := newWorld(7)
+	octaveSets := []struct {
+		octaves                 int
+		lacunarity, persistence float64
+	}{{1, 2, 0.5}, {4, 2, 0.5}, {6, 2.5, 0.7}, {0, 2, 0.5}}
+	for _, o := range octaveSets {
+		lo, hi := 1.0, 0.0
+		for x := -1000; x <= 1000; x += 7 {
+			for z := -1000; z <= 1000; z += 7 {
+				v := w.fbm(float64(x)*0.013, float64(z)*0.013, o.octaves, o.lacunarity, o.persistence)
+				if v < 0 || v > 1 {
+					t.Fatalf("%d octaves gave %v at %d, %d, outside [0, 1]", o.octaves, v, x, z)
+				}
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+		// A sample grid this large should use a good part of the range
+		if hi-lo < 0.3 {
+			t.Errorf("%d octaves only spans %v to %v", o.octaves, lo, hi)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package world
+
+// Greedy meshing merges runs of identical, visible faces in each slice of
+// the chunk into larger quads. Faces only merge when the whole Block (type
+// and state) matches, so textures and states stay correct, and when their
+// ambient occlusion is the same flat value at every corner. Faces with an AO
+// gradient are emitted on their own so the shading isn't stretched. Light is
+// per face, so it only has to match for faces to merge.
+
+// greedyCell is one face in a slice mask
+type greedyCell struct {
+	block Block
+	ao    [4]float32
+	light vertexLight
+}
+
+func (g greedyCell) mergeable() bool {
+	return g.ao[0] == g.ao[1] && g.ao[1] == g.ao[2] && g.ao[2] == g.ao[3]
+}
+
+// faceAxis gives the normal axis (0 x, 1 y, 2 z) and its direction for a face index
+var faceAxis = [6]struct{ axis, dir int }{
+	{2, 1},  // Front (+Z)
+	{2, -1}, // Back (-Z)
+	{0, 1},  // Right (+X)
+	{0, -1}, // Left (-X)
+	{1, 1},  // Top (+Y)
+	{1, -1}, // Bottom (-Y)
+}
+
+func (c *Chunk) appendGreedyFaces(vertices *meshVertices,
+	isTransparent func(self BlockType, x, y, z int) bool,
+	faceAO func(x, y, z, face int) [4]float32,
+	faceLight func(x, y, z, face int) vertexLight) {
+	dims := [3]int{ChunkSize, ChunkHeight, ChunkSize}
+	baseX := float32(c.X * ChunkSize)
+	baseZ := float32(c.Z * ChunkSize)
+
+	for face := 0; face < 6; face++ {
+		d := faceAxis[face].axis
+		u := (d + 1) % 3
+		v := (d + 2) % 3
+		du, dv := dims[u], dims[v]
+
+		var offset [3]int
+		offset[d] = faceAxis[face].dir
+
+		mask := make([]greedyCell, du*dv)
+		visible := make([]bool, du*dv)
+
+		for slice := 0; slice < dims[d]; slice++ {
+			// Build the mask of faces visible in this slice
+			for j := 0; j < dv; j++ {
+				for i := 0; i < du; i++ {
+					var pos [3]int
+					pos[d], pos[u], pos[v] = slice, i, j
+
+					block := c.Get(pos[0], pos[1], pos[2])
+					n := j*du + i
+					visible[n] = block.Type != BlockAir &&
+						isTransparent(block.Type, pos[0]+offset[0], pos[1]+offset[1], pos[2]+offset[2])
+					if visible[n] {
+						mask[n] = greedyCell{block,
+							faceAO(pos[0], pos[1], pos[2], face),
+							faceLight(pos[0], pos[1], pos[2], face)}
+					}
+				}
+			}
+
+			// Merge: grow each face along u, then along v while whole rows match
+			for j := 0; j < dv; j++ {
+				for i := 0; i < du; {
+					n := j*du + i
+					if !visible[n] {
+						i++
+						continue
+					}
+					cell := mask[n]
+					merge := cell.mergeable()
+
+					w := 1
+					for merge && i+w < du && visible[n+w] && mask[n+w] == cell {
+						w++
+					}
+
+					h := 1
+				grow:
+					for merge && j+h < dv {
+						row := (j+h)*du + i
+						for k := 0; k < w; k++ {
+							if !visible[row+k] || mask[row+k] != cell {
+								break grow
+							}
+						}
+						h++
+					}
+
+					var pos, size [3]int
+					pos[d], pos[u], pos[v] = slice, i, j
+					size[d], size[u], size[v] = 1, w, h
+					addQuad(vertices.forBlock(cell.block.Type),
+						baseX+float32(pos[0]), float32(pos[1]), baseZ+float32(pos[2]),
+						float32(size[0]), float32(size[1]), float32(size[2]),
+						face, cell.block, cell.ao, cell.light)
+
+					// Consume the merged area
+					for dj := 0; dj < h; dj++ {
+						for k := 0; k < w; k++ {
+							visible[(j+dj)*du+i+k] = false
+						}
+					}
+					i += w
+				}
+			}
+		}
+	}
+}
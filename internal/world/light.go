@@ -0,0 +1,56 @@
+package world
+
+// ColoredLight switches light between full RGB and brightness-only. With it
+// off every light is reduced to grey at its brightest channel, which keeps
+// the same levels without tinting.
+const ColoredLight = true
+
+// MaxLightLevel is the brightest value of a single light channel
+const MaxLightLevel = 15
+
+// LightColor is an RGB light value, each channel 0 to MaxLightLevel
+type LightColor [3]uint8
+
+// Level is the brightness of the light, its strongest channel
+func (l LightColor) Level() uint8 {
+	return max(l[0], l[1], l[2])
+}
+
+// Mix combines two lights reaching the same cell. Channels are mixed
+// independently, so a red and a blue light overlap as purple.
+func (l LightColor) Mix(other LightColor) LightColor {
+	return LightColor{
+		max(l[0], other[0]),
+		max(l[1], other[1]),
+		max(l[2], other[2]),
+	}
+}
+
+// Attenuate dims every channel by amount, stopping at zero
+func (l LightColor) Attenuate(amount uint8) LightColor {
+	var out LightColor
+	for i, c := range l {
+		if c > amount {
+			out[i] = c - amount
+		}
+	}
+	return out
+}
+
+// Vec returns the light as 0-1 floats for use as a vertex color
+func (l LightColor) Vec() [3]float32 {
+	return [3]float32{
+		float32(l[0]) / MaxLightLevel,
+		float32(l[1]) / MaxLightLevel,
+		float32(l[2]) / MaxLightLevel,
+	}
+}
+
+// effective applies the ColoredLight switch
+func (l LightColor) effective() LightColor {
+	if ColoredLight {
+		return l
+	}
+	level := l.Level()
+	return LightColor{level, level, level}
+}
@@ -0,0 +1,318 @@
+package world
+
+// Lighting: every cell holds a sky light level from 0 (dark) to MaxLightLevel
+// and an RGB block light. Open sky fills straight down at full strength, then
+// spreads sideways and around overhangs losing one level per block. Block
+// light starts at emitters (see EmittedLight) and spreads the same way, each
+// channel on its own. Solid blocks stop light and liquids dim it by an extra
+// level.
+//
+// A fresh chunk is lit on its own (on the worker that generated it). When it
+// joins the world its border is stitched against the loaded neighbors, and an
+// edit relights the 3x3 chunks around it, since light never travels further
+// than MaxLightLevel blocks.
+
+// lightNode is a cell in the world-space light queue
+type lightNode struct {
+	x, y, z int
+}
+
+// lightCost is how much light is lost entering a block, beyond the one level
+// per step. ok is false for blocks light can't enter.
+func lightCost(blockType BlockType) (extra uint8, ok bool) {
+	if IsSolid(blockType) {
+		return 0, false
+	}
+	if IsLiquid(blockType) {
+		return 1, true
+	}
+	return 0, true
+}
+
+// skyBrightness maps a sky light level to a shading multiplier. Each level
+// down is 20% darker, so dim light falls off quickly like it does outdoors.
+var skyBrightness = func() (table [MaxLightLevel + 1]float32) {
+	b := float32(1)
+	for level := MaxLightLevel; level >= 0; level-- {
+		table[level] = b
+		b *= 0.8
+	}
+	return table
+}()
+
+// blockBrightness is the same curve for one block light channel, except
+// that level 0 adds nothing
+var blockBrightness = func() [MaxLightLevel + 1]float32 {
+	table := skyBrightness
+	table[0] = 0
+	return table
+}()
+
+// vertexLight is the light baked into a face: the sky brightness multiplier
+// and the RGB block light added on top
+type vertexLight struct {
+	sky   float32
+	block [3]float32
+}
+
+// fullBright lights a face as if under open sky, for meshes outside the world
+var fullBright = vertexLight{sky: 1}
+
+func newVertexLight(sky uint8, block LightColor) vertexLight {
+	return vertexLight{
+		sky: skyBrightness[sky],
+		block: [3]float32{
+			blockBrightness[block[0]],
+			blockBrightness[block[1]],
+			blockBrightness[block[2]],
+		},
+	}
+}
+
+// packLocal and unpackLocal squeeze a chunk-local cell into one int for the
+// local flood fill queue
+func packLocal(x, y, z int) int32 {
+	return int32(x<<12 | y<<4 | z)
+}
+
+func unpackLocal(i int32) (x, y, z int) {
+	return int(i >> 12), int(i >> 4 & 0xFF), int(i & 0xF)
+}
+
+var lightDirs = [6][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+
+// raiseLight lets the light of a neighboring cell into a cell of c. Returns
+// true if either the sky or block light there went up.
+func (c *Chunk) raiseLight(x, y, z int, sky uint8, block LightColor) bool {
+	extra, ok := lightCost(c.Get(x, y, z).Type)
+	if !ok {
+		return false
+	}
+
+	raised := false
+	if sky > 1+extra && c.SkyLight[x][y][z] < sky-1-extra {
+		c.SkyLight[x][y][z] = sky - 1 - extra
+		raised = true
+	}
+	old := c.BlockLight[x][y][z]
+	if mixed := old.Mix(block.Attenuate(1 + extra)); mixed != old {
+		c.BlockLight[x][y][z] = mixed
+		raised = true
+	}
+	return raised
+}
+
+// computeLight lights the chunk from scratch, ignoring its neighbors.
+// Only touches the chunk itself, so it is safe on a worker goroutine.
+func (c *Chunk) computeLight() {
+	c.SkyLight = [ChunkSize][ChunkHeight][ChunkSize]uint8{}
+	c.BlockLight = [ChunkSize][ChunkHeight][ChunkSize]LightColor{}
+	queue := make([]int32, 0, 4096)
+
+	// Straight down from the sky
+	for x := 0; x < ChunkSize; x++ {
+		for z := 0; z < ChunkSize; z++ {
+			level := uint8(MaxLightLevel)
+			for y := ChunkHeight - 1; y >= 0 && level > 0; y-- {
+				extra, ok := lightCost(c.Get(x, y, z).Type)
+				if !ok {
+					break
+				}
+				level -= min(extra, level)
+				c.SkyLight[x][y][z] = level
+			}
+		}
+	}
+
+	// Only sky lit cells next to a darker column need to spread, most of the
+	// open air above the terrain is already final
+	for x := 0; x < ChunkSize; x++ {
+		for z := 0; z < ChunkSize; z++ {
+			for y := 0; y < ChunkHeight; y++ {
+				level := c.SkyLight[x][y][z]
+				if level > 1 && c.darkerBeside(x, y, z, level-1) {
+					queue = append(queue, packLocal(x, y, z))
+				}
+
+				// Emitters
+				block := c.Get(x, y, z)
+				if light := EmittedLight(block.Type, block.State); light != (LightColor{}) {
+					c.BlockLight[x][y][z] = light
+					queue = append(queue, packLocal(x, y, z))
+				}
+			}
+		}
+	}
+
+	// Sideways into caves and under overhangs, and out from emitters
+	for head := 0; head < len(queue); head++ {
+		x, y, z := unpackLocal(queue[head])
+		sky, block := c.SkyLight[x][y][z], c.BlockLight[x][y][z]
+
+		for _, d := range lightDirs {
+			nx, ny, nz := x+d[0], y+d[1], z+d[2]
+			if nx < 0 || nx >= ChunkSize || ny < 0 || ny >= ChunkHeight || nz < 0 || nz >= ChunkSize {
+				continue
+			}
+			if c.raiseLight(nx, ny, nz, sky, block) {
+				queue = append(queue, packLocal(nx, ny, nz))
+			}
+		}
+	}
+}
+
+// darkerBeside reports whether a horizontal neighbor inside the chunk that
+// light can enter has less sky light than level
+func (c *Chunk) darkerBeside(x, y, z int, level uint8) bool {
+	for _, d := range lightDirs {
+		nx, nz := x+d[0], z+d[2]
+		if d[1] != 0 || nx < 0 || nx >= ChunkSize || nz < 0 || nz >= ChunkSize {
+			continue
+		}
+		if _, ok := lightCost(c.Get(nx, y, nz).Type); ok && c.SkyLight[nx][y][nz] < level {
+			return true
+		}
+	}
+	return false
+}
+
+// spreadLight floods light outward from seeds across loaded chunks. It
+// only ever raises levels. Chunks whose light changed are added to changed.
+func (w *World) spreadLight(queue []lightNode, changed map[*Chunk]bool) {
+	// Most steps stay in the same chunk, skip the map lookup for those
+	var lastKey [2]int
+	var lastChunk *Chunk
+	cellAt := func(x, z int) (*Chunk, int, int) {
+		chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
+		key := chunkKey(chunkX, chunkZ)
+		if lastChunk == nil || key != lastKey {
+			lastKey, lastChunk = key, w.chunks[key]
+		}
+		return lastChunk, localX, localZ
+	}
+
+	for head := 0; head < len(queue); head++ {
+		n := queue[head]
+		c, lx, lz := cellAt(n.x, n.z)
+		if c == nil {
+			continue
+		}
+		sky, block := c.SkyLight[lx][n.y][lz], c.BlockLight[lx][n.y][lz]
+
+		for _, d := range lightDirs {
+			nx, ny, nz := n.x+d[0], n.y+d[1], n.z+d[2]
+			if ny < 0 || ny >= ChunkHeight {
+				continue
+			}
+			nc, nlx, nlz := cellAt(nx, nz)
+			if nc == nil {
+				continue
+			}
+			if nc.raiseLight(nlx, ny, nlz, sky, block) {
+				changed[nc] = true
+				queue = append(queue, lightNode{nx, ny, nz})
+			}
+		}
+	}
+}
+
+// lightFlows reports whether the light in cell a is bright enough to raise
+// the light in the neighboring cell b
+func lightFlows(aSky, bSky uint8, aBlock, bBlock LightColor) bool {
+	return aSky > bSky+1 || aBlock.Attenuate(1).Mix(bBlock) != bBlock
+}
+
+// borderSeeds queues the cells along the edge between a chunk and its loaded
+// neighbors wherever light could flow across
+func (w *World) borderSeeds(c *Chunk, queue []lightNode) []lightNode {
+	baseX, baseZ := c.X*ChunkSize, c.Z*ChunkSize
+
+	// Each side: neighbor offset and the local coordinates of the cell pair
+	// along it, as a function of the position along the edge
+	sides := []struct {
+		dx, dz int
+		cell   func(i int) (x, z, nx, nz int)
+	}{
+		{-1, 0, func(i int) (int, int, int, int) { return 0, i, ChunkSize - 1, i }},
+		{1, 0, func(i int) (int, int, int, int) { return ChunkSize - 1, i, 0, i }},
+		{0, -1, func(i int) (int, int, int, int) { return i, 0, i, ChunkSize - 1 }},
+		{0, 1, func(i int) (int, int, int, int) { return i, ChunkSize - 1, i, 0 }},
+	}
+
+	for _, side := range sides {
+		n := w.chunks[chunkKey(c.X+side.dx, c.Z+side.dz)]
+		if n == nil {
+			continue
+		}
+		for i := 0; i < ChunkSize; i++ {
+			x, z, nx, nz := side.cell(i)
+			for y := 0; y < ChunkHeight; y++ {
+				aSky, bSky := c.SkyLight[x][y][z], n.SkyLight[nx][y][nz]
+				aBlock, bBlock := c.BlockLight[x][y][z], n.BlockLight[nx][y][nz]
+				if lightFlows(aSky, bSky, aBlock, bBlock) {
+					queue = append(queue, lightNode{baseX + x, y, baseZ + z})
+				}
+				if lightFlows(bSky, aSky, bBlock, aBlock) {
+					queue = append(queue, lightNode{
+						(c.X+side.dx)*ChunkSize + nx, y, (c.Z+side.dz)*ChunkSize + nz})
+				}
+			}
+		}
+	}
+	return queue
+}
+
+// stitchLight lets light flow between a newly added chunk and its loaded
+// neighbors. Returns the neighbors whose light changed and need remeshing.
+func (w *World) stitchLight(c *Chunk) map[*Chunk]bool {
+	changed := make(map[*Chunk]bool)
+	w.spreadLight(w.borderSeeds(c, nil), changed)
+	delete(changed, c)
+	return changed
+}
+
+// relightAround recomputes light for the 3x3 chunks centered on c after an
+// edit and returns every chunk whose light changed. Breaking an emitter or
+// opening a hole to the sky is handled the same way as adding one, since the
+// region is lit again from nothing.
+func (w *World) relightAround(c *Chunk) map[*Chunk]bool {
+	changed := make(map[*Chunk]bool)
+
+	var region []*Chunk
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			if n := w.chunks[chunkKey(c.X+dx, c.Z+dz)]; n != nil {
+				region = append(region, n)
+			}
+		}
+	}
+
+	type snapshot struct {
+		sky   [ChunkSize][ChunkHeight][ChunkSize]uint8
+		block [ChunkSize][ChunkHeight][ChunkSize]LightColor
+	}
+	before := make([]snapshot, len(region))
+	for i, n := range region {
+		before[i] = snapshot{n.SkyLight, n.BlockLight}
+		n.computeLight()
+	}
+
+	// Let light back in across every edge, including from the chunks
+	// around the region which kept their old light
+	var queue []lightNode
+	for _, n := range region {
+		queue = w.borderSeeds(n, queue)
+	}
+	w.spreadLight(queue, changed)
+
+	// The spread marks every region chunk it relit, compare against the old
+	// light instead so unchanged chunks aren't remeshed
+	for i, n := range region {
+		if n.SkyLight != before[i].sky || n.BlockLight != before[i].block {
+			changed[n] = true
+		} else {
+			delete(changed, n)
+		}
+	}
+	return changed
+}
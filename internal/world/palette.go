@@ -0,0 +1,96 @@
+package world
+
+// blockStore holds a chunk's blocks as indices into a palette of the
+// distinct blocks it uses, packed into 64-bit words. A chunk rarely uses
+// more than 16 blocks, so a cell costs 4 bits instead of the 2 bytes of a
+// Block. Air is always palette entry 0, so an all-air chunk, the zero
+// value, needs no indices at all.
+type blockStore struct {
+	palette []Block
+	// Bits per index: 0 while the palette has one entry, then 1, 2, 4, 8 or
+	// 16 so an index never straddles two words
+	bits uint
+	data []uint64
+}
+
+// blockIndex orders cells in columns, x, z then y, the same order save
+// files run in, so vertical runs of air and stone sit together
+func blockIndex(x, y, z int) int {
+	return (x*ChunkSize+z)*ChunkHeight + y
+}
+
+func (s *blockStore) get(i int) Block {
+	if s.bits == 0 {
+		if len(s.palette) == 0 {
+			return Block{}
+		}
+		return s.palette[0]
+	}
+	bit := uint(i) * s.bits
+	index := s.data[bit/64] >> (bit % 64) & (1<<s.bits - 1)
+	return s.palette[index]
+}
+
+func (s *blockStore) set(i int, block Block) {
+	if s.palette == nil {
+		if block == (Block{}) {
+			return
+		}
+		s.palette = []Block{{}}
+	}
+	index := s.paletteIndex(block)
+	if s.bits == 0 && index == 0 {
+		return
+	}
+	bit := uint(i) * s.bits
+	mask := uint64(1<<s.bits-1) << (bit % 64)
+	s.data[bit/64] = s.data[bit/64]&^mask | uint64(index)<<(bit%64)
+}
+
+// paletteIndex finds block in the palette, adding it and widening the
+// indices if it is new. Entries aren't dropped when the last cell using
+// them changes, a reloaded chunk starts with a fresh palette.
+func (s *blockStore) paletteIndex(block Block) int {
+	for i, b := range s.palette {
+		if b == block {
+			return i
+		}
+	}
+	s.palette = append(s.palette, block)
+	if len(s.palette) > 1<<s.bits {
+		bits := s.bits * 2
+		if bits == 0 {
+			bits = 1
+		}
+		s.resize(bits)
+	}
+	return len(s.palette) - 1
+}
+
+// resize repacks every index at a new width
+func (s *blockStore) resize(bits uint) {
+	old := *s
+	s.bits = bits
+	s.data = make([]uint64, chunkBlockCount*int(bits)/64)
+	if old.bits == 0 {
+		// Every cell is palette entry 0, already zero
+		return
+	}
+	for i := 0; i < chunkBlockCount; i++ {
+		bit := uint(i) * old.bits
+		index := old.data[bit/64] >> (bit % 64) & (1<<old.bits - 1)
+		bit = uint(i) * bits
+		s.data[bit/64] |= index << (bit % 64)
+	}
+}
+
+// Get returns the block at chunk-local x, y, z
+func (c *Chunk) Get(x, y, z int) Block {
+	return c.blocks.get(blockIndex(x, y, z))
+}
+
+// Set replaces the block at chunk-local x, y, z. It doesn't relight,
+// remesh or mark the chunk for saving, World.SetBlock does that.
+func (c *Chunk) Set(x, y, z int, block Block) {
+	c.blocks.set(blockIndex(x, y, z), block)
+}
@@ -0,0 +1,82 @@
+package world
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestBlockStoreRoundTrip(t *testing.T) {
+	var store blockStore
+	var dense [chunkBlockCount]Block
+
+	// Enough distinct blocks at each stage to need the next index width
+	stages := []struct {
+		blocks int
+		bits   uint
+	}{{2, 1}, {3, 2}, {5, 4}, {17, 8}, {257, 16}}
+
+	cell := 0
+	for _, stage := range stages {
+		for n := 1; n < stage.blocks; n++ {
+			block := Block{Type: BlockType(n % 256), State: uint8(n / 256)}
+			// Spread each block over a few cells, striding so runs break up
+			for i := 0; i < 3; i++ {
+				cell = (cell + 7919) % chunkBlockCount
+				store.set(cell, block)
+				dense[cell] = block
+			}
+		}
+		if store.bits != stage.bits {
+			t.Errorf("%d blocks in the palette use %d bits, want %d", len(store.palette), store.bits, stage.bits)
+		}
+		for i := range dense {
+			if got := store.get(i); got != dense[i] {
+				t.Fatalf("at %d bits cell %d is %v, want %v", stage.bits, i, got, dense[i])
+			}
+		}
+	}
+
+	var decoded blockStore
+	if err := decodeBlocks(encodeBlocks(&store), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for i := range dense {
+		if got := decoded.get(i); got != dense[i] {
+			t.Fatalf("decoded cell %d is %v, want %v", i, got, dense[i])
+		}
+	}
+}
+
+func TestBlockStoreAllAir(t *testing.T) {
+	var store blockStore
+	store.set(blockIndex(3, 40, 5), Block{})
+	if store.data != nil || store.get(blockIndex(3, 40, 5)) != (Block{}) {
+		t.Error("writing air to an empty store allocated indices")
+	}
+}
+
+// BenchmarkChunkMemory generates 1000 chunks, about a render distance of 16,
+// and reports the heap they keep alive. Run it with -benchtime=1x.
+func BenchmarkChunkMemory(b *testing.B) {
+	const count = 1000
+	w := newWorld(1)
+	for i := 0; i < b.N; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+
+		chunks := make([]*Chunk, 0, count)
+		for x := 0; x < 40; x++ {
+			for z := 0; z < count/40; z++ {
+				chunks = append(chunks, w.generateChunk(x-20, z-12))
+			}
+		}
+
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(chunks)
+		heap := float64(after.HeapAlloc - before.HeapAlloc)
+		b.ReportMetric(heap/(1<<20), "MB/1000chunks")
+		b.ReportMetric(heap/1024/count, "KB/chunk")
+	}
+}
@@ -0,0 +1,164 @@
+package world
+
+import "github.com/go-gl/mathgl/mgl32"
+
+// UseHandler runs when the player right-clicks a block. Returning true
+// consumes the click so no block gets placed against it.
+type UseHandler func(w *World, x, y, z int) bool
+
+// BlockState is one visual/behavioral variant of a multi-state block
+type BlockState struct {
+	Name    string
+	Texture [2]float32
+	Light   LightColor
+}
+
+// ItemDrop is what a broken block hands to the player
+type ItemDrop struct {
+	Type  BlockType
+	Count int
+}
+
+// BlockDef holds per-type behavior that doesn't belong in the block data itself.
+// States is empty for ordinary single-state blocks. A nil Drops means the
+// block drops itself. Liquid blocks can be walked and seen through.
+// Translucent blocks are drawn in the blended pass after everything else.
+// Orientable blocks are placed running along an Axis, like logs.
+type BlockDef struct {
+	Name        string
+	States      []BlockState
+	Drops       []ItemDrop
+	OnUse       UseHandler
+	Liquid      bool
+	Translucent bool
+	Orientable  bool
+	Light       LightColor // Emitted light, zero for blocks that don't glow
+	// Sound set used for breaking, placing and walking on the block, e.g.
+	// "dirt" or "wood". Empty means stone.
+	Sound string
+	// Flat color standing in for the block where textures are too small to
+	// read, e.g. hotbar slots and the minimap
+	Color mgl32.Vec3
+}
+
+var registry [256]BlockDef
+
+func init() {
+	RegisterBlock(BlockAir, BlockDef{Name: "Air"})
+	RegisterBlock(BlockDirt, BlockDef{Name: "Dirt", Color: mgl32.Vec3{0.6, 0.4, 0.2}, Sound: "dirt"})
+	RegisterBlock(BlockGrass, BlockDef{Name: "Grass", Color: mgl32.Vec3{0.2, 0.8, 0.2}, Sound: "grass", Drops: []ItemDrop{{Type: BlockDirt, Count: 1}}})
+	RegisterBlock(BlockStone, BlockDef{Name: "Stone", Color: mgl32.Vec3{0.5, 0.5, 0.5}})
+	RegisterBlock(BlockSnow, BlockDef{Name: "Snow", Color: mgl32.Vec3{1.0, 1.0, 1.0}, Sound: "snow"})
+	RegisterBlock(BlockSand, BlockDef{Name: "Sand", Color: mgl32.Vec3{0.9, 0.8, 0.6}, Sound: "sand"})
+	RegisterBlock(BlockWood, BlockDef{Name: "Wood", Color: mgl32.Vec3{0.5, 0.3, 0.1}, Sound: "wood"})
+
+	RegisterBlock(BlockLamp, BlockDef{
+		Name:  "Lamp",
+		Color: mgl32.Vec3{0.9, 0.9, 0.8},
+		Sound: "glass",
+		States: []BlockState{
+			{Name: "Off", Texture: TexLampOff},
+			{Name: "On", Texture: TexLampOn, Light: LightColor{15, 11, 6}}, // Warm orange
+		},
+		OnUse: cycleState,
+	})
+
+	RegisterBlock(BlockWater, BlockDef{Name: "Water", Color: mgl32.Vec3{0.2, 0.4, 0.9}, Liquid: true, Translucent: true})
+	RegisterBlock(BlockLog, BlockDef{Name: "Log", Color: mgl32.Vec3{0.4, 0.25, 0.1}, Sound: "wood", Orientable: true})
+	RegisterBlock(BlockLeaves, BlockDef{Name: "Leaves", Color: mgl32.Vec3{0.15, 0.5, 0.15}, Sound: "grass"})
+	RegisterBlock(BlockCoalOre, BlockDef{Name: "Coal Ore", Color: mgl32.Vec3{0.25, 0.25, 0.25}})
+	RegisterBlock(BlockIronOre, BlockDef{Name: "Iron Ore", Color: mgl32.Vec3{0.7, 0.55, 0.45}})
+	RegisterBlock(BlockGlowstone, BlockDef{Name: "Glowstone", Color: mgl32.Vec3{1.0, 0.8, 0.4}, Sound: "glass", Light: LightColor{14, 12, 8}}) // Warm yellow
+}
+
+// RegisterBlock sets (or replaces) the definition for a block type
+func RegisterBlock(blockType BlockType, def BlockDef) {
+	registry[blockType] = def
+}
+
+// GetBlockDef returns the registered definition for a block type
+func GetBlockDef(blockType BlockType) *BlockDef {
+	return &registry[blockType]
+}
+
+// BlockColor returns the flat color of a block type, white if it has none
+func BlockColor(blockType BlockType) mgl32.Vec3 {
+	if color := registry[blockType].Color; color != (mgl32.Vec3{}) {
+		return color
+	}
+	return mgl32.Vec3{1, 1, 1}
+}
+
+// SoundOf returns the sound set of a block type
+func SoundOf(blockType BlockType) string {
+	if sound := registry[blockType].Sound; sound != "" {
+		return sound
+	}
+	return "stone"
+}
+
+// IsLiquid reports whether a block type is a liquid
+func IsLiquid(blockType BlockType) bool {
+	return registry[blockType].Liquid
+}
+
+// IsTranslucent reports whether a block type is drawn with alpha blending
+func IsTranslucent(blockType BlockType) bool {
+	return registry[blockType].Translucent
+}
+
+// IsSolid reports whether a block type stops movement and raycasts
+func IsSolid(blockType BlockType) bool {
+	return blockType != BlockAir && !registry[blockType].Liquid
+}
+
+// EmittedLight returns the light a block gives off in the given state
+func EmittedLight(blockType BlockType, state uint8) LightColor {
+	def := &registry[blockType]
+	state &= stateVariantMask
+	if int(state) < len(def.States) {
+		return def.States[state].Light.effective()
+	}
+	return def.Light.effective()
+}
+
+// BlockByName looks up a block type by its registered name (case-sensitive)
+func BlockByName(name string) (BlockType, bool) {
+	for i := range registry {
+		if registry[i].Name != "" && registry[i].Name == name {
+			return BlockType(i), true
+		}
+	}
+	return BlockAir, false
+}
+
+// DropsFor returns the items yielded by breaking a block of the given type
+func DropsFor(blockType BlockType) []ItemDrop {
+	if blockType == BlockAir {
+		return nil
+	}
+	if drops := registry[blockType].Drops; drops != nil {
+		return drops
+	}
+	return []ItemDrop{{Type: blockType, Count: 1}}
+}
+
+// StateName returns the display name of a block state, or "" for single-state blocks
+func StateName(blockType BlockType, state uint8) string {
+	states := registry[blockType].States
+	state &= stateVariantMask
+	if int(state) >= len(states) {
+		return ""
+	}
+	return states[state].Name
+}
+
+// cycleState advances a multi-state block to its next state
+func cycleState(w *World, x, y, z int) bool {
+	states := GetBlockDef(w.GetBlock(x, y, z)).States
+	if len(states) < 2 {
+		return false
+	}
+	next := (w.GetBlockState(x, y, z) + 1) % uint8(len(states))
+	return w.SetBlockState(x, y, z, next)
+}
@@ -0,0 +1,310 @@
+package world
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+
+	"voxel-game/internal/errs"
+
+	"github.com/ojrac/opensimplex-go"
+)
+
+// World saves are a directory holding a small header with the seed plus one
+// file per edited chunk. Chunks that were never edited aren't written, they
+// regenerate identically from the seed.
+//
+// Every file starts with a magic string and SaveVersion and ends with a
+// CRC32 of everything before it, so saves from another version or damaged
+// files are rejected instead of loading garbage.
+//
+// Chunk blocks are run-length encoded column by column (x, z, then y), since
+// columns are mostly long runs of stone, then air:
+//
+//	magic "VXCK" | version u16 | seed i64 | x i32 | z i32 | runs u32 |
+//	runs × (count u16, type u8, state u8) | crc32 u32
+
+const (
+	SaveVersion = 1
+
+	worldMagic    = "VXWD"
+	chunkMagic    = "VXCK"
+	worldFileName = "world.dat"
+
+	chunkBlockCount = ChunkSize * ChunkHeight * ChunkSize
+)
+
+// Save writes the world header and every dirty chunk (edited since it was
+// last saved) into dir, creating it if needed. Later edits to chunks that unload
+// are saved to the same directory automatically. Saving into a different
+// directory than the world was loaded from doesn't copy the old chunk files.
+func (w *World) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	var header bytes.Buffer
+	header.WriteString(worldMagic)
+	binary.Write(&header, binary.LittleEndian, uint16(SaveVersion))
+	binary.Write(&header, binary.LittleEndian, w.gen.Seed)
+	if err := writeChecksummed(filepath.Join(dir, worldFileName), header.Bytes()); err != nil {
+		return err
+	}
+
+	if dir != w.saveDir {
+		w.saveDir = dir
+		w.saved = make(map[[2]int]bool)
+	}
+
+	for _, chunk := range w.chunks {
+		if chunk.dirty {
+			if err := w.saveChunk(chunk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Load reads the world saved in dir. The saved seed replaces the current
+// one, and the chunks around spawn are rebuilt. Saved chunks are then read
+// back as they come into range instead of being regenerated.
+//
+// Load must be called before chunk streaming starts. A missing save returns
+// an error matching os.ErrNotExist and still makes dir the directory edited
+// chunks are saved to as they unload, so a new world doesn't lose them
+// before the first Save. Any other error leaves dir alone: a damaged save
+// must not be written over, so edited chunks stay loaded instead.
+func (w *World) Load(dir string) error {
+	if w.jobs != nil {
+		return errors.New("world: Load called after chunk streaming started")
+	}
+
+	path := filepath.Join(dir, worldFileName)
+	data, err := readChecksummed(path)
+	if errors.Is(err, os.ErrNotExist) {
+		w.saveDir = dir
+		w.saved = make(map[[2]int]bool)
+	}
+	if err != nil {
+		return err
+	}
+
+	r := bytes.NewReader(data)
+	var seed int64
+	if err := readHeader(r, worldMagic); err != nil {
+		return &errs.SaveFormatError{Path: path, Reason: err.Error()}
+	}
+	if err := binary.Read(r, binary.LittleEndian, &seed); err != nil {
+		return &errs.SaveFormatError{Path: path, Reason: "truncated header"}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "chunk_*.dat"))
+	if err != nil {
+		return err
+	}
+	saved := make(map[[2]int]bool, len(files))
+	for _, file := range files {
+		var x, z int
+		if _, err := fmt.Sscanf(filepath.Base(file), "chunk_%d_%d.dat", &x, &z); err == nil {
+			saved[chunkKey(x, z)] = true
+		}
+	}
+
+	w.gen.Seed = seed
+	w.noise = opensimplex.NewNormalized(seed)
+	w.saveDir = dir
+	w.saved = saved
+
+	// Throw away chunks generated from the old seed
+	for key, chunk := range w.chunks {
+		chunk.deleteMeshes()
+		delete(w.chunks, key)
+	}
+	w.chunkListDirty = true
+	w.generateSpawn()
+	return nil
+}
+
+// loadOrGenerateChunk returns the saved copy of a chunk if there is one,
+// otherwise generates it. A damaged save is reported and regenerated.
+func (w *World) loadOrGenerateChunk(chunkX, chunkZ int) *Chunk {
+	if w.saved[chunkKey(chunkX, chunkZ)] {
+		chunk, err := w.loadChunk(chunkX, chunkZ)
+		if err == nil {
+			return chunk
+		}
+		w.reportSaveError(err)
+	}
+	return w.generateChunk(chunkX, chunkZ)
+}
+
+func (w *World) chunkPath(chunkX, chunkZ int) string {
+	return filepath.Join(w.saveDir, fmt.Sprintf("chunk_%d_%d.dat", chunkX, chunkZ))
+}
+
+func (w *World) saveChunk(chunk *Chunk) error {
+	// A new world's directory doesn't exist until the first Save
+	if err := os.MkdirAll(w.saveDir, 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(chunkMagic)
+	binary.Write(&buf, binary.LittleEndian, uint16(SaveVersion))
+	binary.Write(&buf, binary.LittleEndian, w.gen.Seed)
+	binary.Write(&buf, binary.LittleEndian, int32(chunk.X))
+	binary.Write(&buf, binary.LittleEndian, int32(chunk.Z))
+
+	runs := encodeBlocks(&chunk.blocks)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(runs)/4))
+	buf.Write(runs)
+
+	if err := writeChecksummed(w.chunkPath(chunk.X, chunk.Z), buf.Bytes()); err != nil {
+		return err
+	}
+	chunk.dirty = false
+	w.saved[chunkKey(chunk.X, chunk.Z)] = true
+	return nil
+}
+
+func (w *World) loadChunk(chunkX, chunkZ int) (*Chunk, error) {
+	path := w.chunkPath(chunkX, chunkZ)
+	data, err := readChecksummed(path)
+	if err != nil {
+		return nil, err
+	}
+
+	formatErr := func(reason string) error {
+		return &errs.SaveFormatError{Path: path, Reason: reason}
+	}
+
+	r := bytes.NewReader(data)
+	if err := readHeader(r, chunkMagic); err != nil {
+		return nil, formatErr(err.Error())
+	}
+	var fields struct {
+		Seed int64
+		X, Z int32
+		Runs uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &fields); err != nil {
+		return nil, formatErr("truncated header")
+	}
+	if fields.Seed != w.gen.Seed {
+		return nil, formatErr("saved with a different seed")
+	}
+	if int(fields.X) != chunkX || int(fields.Z) != chunkZ {
+		return nil, formatErr(fmt.Sprintf("holds chunk %d,%d", fields.X, fields.Z))
+	}
+	if r.Len() != int(fields.Runs)*4 {
+		return nil, formatErr("block data length mismatch")
+	}
+
+	chunk := &Chunk{X: chunkX, Z: chunkZ}
+	if err := decodeBlocks(data[len(data)-r.Len():], &chunk.blocks); err != nil {
+		return nil, formatErr(err.Error())
+	}
+	chunk.computeLight()
+	return chunk, nil
+}
+
+// reportSaveError is where chunk save and load failures that can't be
+// returned to a caller end up
+func (w *World) reportSaveError(err error) {
+	fmt.Println("[World]", err)
+}
+
+// encodeBlocks run-length encodes a chunk's blocks, 4 bytes per run
+func encodeBlocks(blocks *blockStore) []byte {
+	out := make([]byte, 0, 1024)
+	var current Block
+	count := 0
+
+	flush := func() {
+		if count > 0 {
+			out = binary.LittleEndian.AppendUint16(out, uint16(count))
+			out = append(out, byte(current.Type), current.State)
+		}
+	}
+
+	// The store's order is the file's, x, z, y
+	for i := 0; i < chunkBlockCount; i++ {
+		block := blocks.get(i)
+		if count > 0 && block == current && count < 0xFFFF {
+			count++
+			continue
+		}
+		flush()
+		current, count = block, 1
+	}
+	flush()
+	return out
+}
+
+// decodeBlocks is the inverse of encodeBlocks. The runs must cover the
+// chunk exactly.
+func decodeBlocks(runs []byte, blocks *blockStore) error {
+	i := 0
+	for ; len(runs) >= 4; runs = runs[4:] {
+		count := int(binary.LittleEndian.Uint16(runs))
+		block := Block{Type: BlockType(runs[2]), State: runs[3]}
+		if count == 0 || i+count > chunkBlockCount {
+			return errors.New("block runs overflow the chunk")
+		}
+		for end := i + count; i < end; i++ {
+			blocks.set(i, block)
+		}
+	}
+	if i != chunkBlockCount {
+		return errors.New("block runs don't fill the chunk")
+	}
+	return nil
+}
+
+func readHeader(r *bytes.Reader, magic string) error {
+	got := make([]byte, len(magic))
+	var version uint16
+	if _, err := r.Read(got); err != nil || string(got) != magic {
+		return errors.New("not a save file")
+	}
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return errors.New("truncated header")
+	}
+	if version != SaveVersion {
+		return fmt.Errorf("format version %d, expected %d", version, SaveVersion)
+	}
+	return nil
+}
+
+// writeChecksummed writes data followed by its CRC32. The file is written
+// under a temporary name and renamed so a crash never leaves half a file.
+func writeChecksummed(path string, data []byte) error {
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readChecksummed reads a file written by writeChecksummed and returns the
+// data without the checksum
+func readChecksummed(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, &errs.SaveFormatError{Path: path, Reason: "file too short"}
+	}
+	body := data[:len(data)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, &errs.SaveFormatError{Path: path, Reason: "checksum mismatch"}
+	}
+	return body, nil
+}
@@ -0,0 +1,68 @@
+package world
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCorruptSaveLeavesItAlone(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		worldFileName:    []byte("VXWD damaged header"),
+		"chunk_0_0.dat":  []byte("old chunk data"),
+		"chunk_30_0.dat": []byte("another old chunk"),
+		"notes_left.txt": []byte("player's own file"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := newWorld(1)
+	err := w.Load(dir)
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load of a corrupt save returned %v, want a format error", err)
+	}
+	if w.saveDir != "" {
+		t.Errorf("save directory set to %q after a failed load", w.saveDir)
+	}
+
+	// An edited chunk streaming out must stay loaded, not be written there
+	chunk := &Chunk{X: 30, Z: 0}
+	chunk.Set(1, 20, 1, Block{Type: BlockStone})
+	chunk.dirty = true
+	w.chunks[chunkKey(30, 0)] = chunk
+	w.unloadFarChunks(0, 0)
+	if _, ok := w.chunks[chunkKey(30, 0)]; !ok {
+		t.Error("dirty chunk unloaded with nowhere to save it")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(files) {
+		t.Errorf("directory holds %d files after the failed load, want %d", len(entries), len(files))
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s changed after the failed load", name)
+		}
+	}
+}
+
+func TestLoadMissingSaveAdoptsDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "world")
+	w := newWorld(1)
+	if err := w.Load(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load of a missing save returned %v, want os.ErrNotExist", err)
+	}
+	if w.saveDir != dir {
+		t.Errorf("save directory %q, want %q for a new world", w.saveDir, dir)
+	}
+}
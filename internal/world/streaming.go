@@ -0,0 +1,119 @@
+package world
+
+import "slices"
+
+// Chunk streaming: block generation runs on a pool of worker goroutines and
+// finished chunks are handed back over a channel. Meshing and every GL call
+// stay on the main thread in IntegrateReadyChunks. Workers generate with a
+// copy of the world's generation settings taken when the pool starts, so
+// the main thread can't race them by changing the exported cave and ore
+// fields. The noise is only read and safe to share.
+
+// SetWorkerCount sets the generation pool size. It only takes effect before
+// the first UpdateChunksAsync call starts the pool.
+func (w *World) SetWorkerCount(n int) {
+	if n > 0 && w.jobs == nil {
+		w.workerCount = n
+	}
+}
+
+func (w *World) startWorkers() {
+	// Buffer the whole render area so enqueueing never blocks the main thread
+	side := MaxRenderDistance*2 + 1
+	w.jobs = make(chan [2]int, side*side)
+	w.results = make(chan *Chunk, side*side)
+
+	generator := w.generator()
+	for i := 0; i < w.workerCount; i++ {
+		go func() {
+			for key := range w.jobs {
+				w.results <- generator.generateChunk(key[0], key[1])
+			}
+		}()
+	}
+}
+
+// generator is a world holding only what generateChunk reads, copied from
+// w. Load can't change the seed once streaming has started, so the copy
+// stays in step with w.
+func (w *World) generator() *World {
+	return &World{
+		noise:            w.noise,
+		gen:              w.gen,
+		CaveFrequency:    w.CaveFrequency,
+		CaveThreshold:    w.CaveThreshold,
+		CaveSurfaceDepth: w.CaveSurfaceDepth,
+		Ores:             slices.Clone(w.Ores),
+	}
+}
+
+// UpdateChunksAsync queues generation for missing chunks in render distance,
+// nearest first, and unloads far ones. New chunks appear once
+// IntegrateReadyChunks picks them up.
+func (w *World) UpdateChunksAsync(playerX, playerZ float32) {
+	if w.jobs == nil {
+		w.startWorkers()
+	}
+
+	playerChunkX, playerChunkZ := ChunkOf(playerX, playerZ)
+
+enqueue:
+	for _, key := range w.missingChunks(playerChunkX, playerChunkZ) {
+		// Saved chunks are small to read, load them here rather than
+		// regenerating them on a worker
+		if w.saved[key] {
+			chunk, err := w.loadChunk(key[0], key[1])
+			if err == nil {
+				w.addChunk(chunk)
+				continue
+			}
+			w.reportSaveError(err)
+			delete(w.saved, key)
+		}
+
+		select {
+		case w.jobs <- key:
+			w.pending[key] = true
+		default:
+			// Queue is full, the rest get picked up on a later update
+			break enqueue
+		}
+	}
+
+	w.unloadFarChunks(playerChunkX, playerChunkZ)
+}
+
+// IntegrateReadyChunks adds up to ChunkBudget chunks the workers have
+// finished and meshes them. The rest wait in the results channel for the
+// next call. Must be called from the GL thread, once per frame.
+func (w *World) IntegrateReadyChunks() {
+	for budget := max(w.ChunkBudget, 1); budget > 0; {
+		select {
+		case chunk := <-w.results:
+			key := chunkKey(chunk.X, chunk.Z)
+			delete(w.pending, key)
+			if _, exists := w.chunks[key]; exists {
+				continue
+			}
+			w.addChunk(chunk)
+			budget--
+		default:
+			return
+		}
+	}
+}
+
+// Close stops the generation workers. Chunks still in flight are dropped.
+func (w *World) Close() {
+	if w.jobs != nil {
+		close(w.jobs)
+	}
+}
+
+// Cleanup frees the GPU buffers of every loaded chunk. The blocks stay, so
+// the world can still be saved. GL thread only.
+func (w *World) Cleanup() {
+	for _, chunk := range w.chunks {
+		chunk.deleteMeshes()
+	}
+}
@@ -0,0 +1,117 @@
+package world
+
+// Trees are placed after terrain generation. Every decision comes from a hash
+// of the seed and world position, so the same seed always grows the same
+// forest regardless of the order chunks are generated in.
+//
+// Chunks are generated independently on worker goroutines, so a tree can't
+// write into its neighbors. Trunks are kept far enough from the chunk edge
+// that the whole canopy fits inside the chunk.
+
+const (
+	treeCanopyRadius = 2
+	treeMinTrunk     = 4
+	treeMaxTrunk     = 6
+)
+
+// decorateChunk runs the structure passes over a generated chunk. How many
+// trees grow depends on each column's biome.
+func (w *World) decorateChunk(chunk *Chunk, heights *[ChunkSize][ChunkSize]int, biomes *[ChunkSize][ChunkSize]Biome) {
+	for x := treeCanopyRadius; x < ChunkSize-treeCanopyRadius; x++ {
+		for z := treeCanopyRadius; z < ChunkSize-treeCanopyRadius; z++ {
+			surface := heights[x][z]
+			ground := chunk.Get(x, surface, z).Type
+			if surface <= SeaLevel || (ground != BlockGrass && ground != BlockSnow) {
+				continue
+			}
+			treeChance := biomeDefs[biomes[x][z]].treeChance
+			if treeChance == 0 {
+				continue
+			}
+
+			worldX := chunk.X*ChunkSize + x
+			worldZ := chunk.Z*ChunkSize + z
+
+			// Low frequency noise groups trees into groves and clearings
+			forest := w.noise.Eval2(float64(worldX)*0.01+500, float64(worldZ)*0.01+500)
+			chance := treeChance * 2 * forest
+
+			h := w.positionHash(worldX, worldZ)
+			if float64(h&0xFFFF)/0x10000 >= chance {
+				continue
+			}
+			trunk := treeMinTrunk + int((h>>16)%(treeMaxTrunk-treeMinTrunk+1))
+			placeTree(chunk, x, surface+1, z, trunk, h>>32)
+		}
+	}
+}
+
+// placeTree grows a trunk from x,y,z with a leaf canopy around its top.
+// Leaves only fill air, so neighboring trees don't cut into each other.
+func placeTree(chunk *Chunk, x, y, z, trunk int, rnd uint64) {
+	top := y + trunk - 1
+	if top+1 >= ChunkHeight {
+		return
+	}
+
+	setLeaves := func(lx, ly, lz int) {
+		if chunk.Get(lx, ly, lz).Type == BlockAir {
+			chunk.Set(lx, ly, lz, Block{Type: BlockLeaves})
+		}
+	}
+
+	// Two wide layers below the top, with corners randomly trimmed
+	for ly := top - 2; ly <= top-1; ly++ {
+		for dx := -treeCanopyRadius; dx <= treeCanopyRadius; dx++ {
+			for dz := -treeCanopyRadius; dz <= treeCanopyRadius; dz++ {
+				if abs(dx) == treeCanopyRadius && abs(dz) == treeCanopyRadius {
+					keep := rnd&1 == 1
+					rnd >>= 1
+					if !keep {
+						continue
+					}
+				}
+				setLeaves(x+dx, ly, z+dz)
+			}
+		}
+	}
+
+	// Narrow layer around the top of the trunk and a plus shape above it
+	for dx := -1; dx <= 1; dx++ {
+		for dz := -1; dz <= 1; dz++ {
+			setLeaves(x+dx, top, z+dz)
+		}
+	}
+	setLeaves(x, top+1, z)
+	setLeaves(x+1, top+1, z)
+	setLeaves(x-1, top+1, z)
+	setLeaves(x, top+1, z+1)
+	setLeaves(x, top+1, z-1)
+
+	// Trunk last so it replaces the leaves grown around it
+	for ly := y; ly <= top; ly++ {
+		chunk.Set(x, ly, z, Block{Type: BlockLog})
+	}
+}
+
+// positionHash gives a well mixed 64-bit value for a column of the world
+func (w *World) positionHash(x, z int) uint64 {
+	h := uint64(w.gen.Seed)
+	h ^= uint64(x) * 0x9E3779B97F4A7C15
+	h ^= uint64(z) * 0xC2B2AE3D27D4EB4F
+
+	// splitmix64 finalizer
+	h ^= h >> 30
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 27
+	h *= 0x94D049BB133111EB
+	h ^= h >> 31
+	return h
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
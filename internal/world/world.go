@@ -2,42 +2,171 @@ package world
 
 import (
 	"math"
+	"runtime"
+	"sort"
 
-	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/ojrac/opensimplex-go"
 )
 
 const (
-	ChunkSize      = 16
-	ChunkHeight    = 256
-	RenderDistance = 16
+	ChunkSize   = 16
+	ChunkHeight = 256
+
+	// Render distance in chunks. 16 loads a 33x33 area, too much for many machines.
+	DefaultRenderDistance = 8
+	MinRenderDistance     = 2
+	MaxRenderDistance     = 32
+
+	// New chunks added per UpdateChunks or IntegrateReadyChunks call
+	DefaultChunkBudget = 4
+
+	// Air at or below this height is filled with water during generation
+	SeaLevel = 32
 )
 
+// Block is one cell of the world, two bytes. State packs the index into the
+// registry's States (0 for single-state blocks) in its low bits and the
+// Axis of an Orientable block in the top two.
 type Block struct {
-	Type BlockType
+	Type  BlockType
+	State uint8
 }
 
 type World struct {
 	chunks map[[2]int]*Chunk
 	noise  opensimplex.Noise
+	gen    GenConfig
+
+	renderDistance int
+	meshUpload     MeshUploadMode
+	// No GPU side, chunks count as meshed without uploading anything
+	headless bool
+
+	editValidator EditValidator
+
+	// Called after SetBlock changes a block, see AddBlockChangedListener
+	OnBlockChanged BlockChangedFunc
+
+	// Merge coplanar faces into larger quads when meshing
+	UseGreedyMeshing bool
+
+	// Most new chunks added per UpdateChunks or IntegrateReadyChunks call,
+	// so a whole ring of missing chunks loads over several frames instead
+	// of freezing one. Values below 1 count as 1.
+	ChunkBudget int
+
+	// Caves are carved where two 3D noise fields are both within
+	// CaveThreshold of their midpoint, which traces winding tunnels.
+	// Changes only affect chunks generated afterwards, and once chunk
+	// streaming starts not even those, see startWorkers.
+	CaveFrequency    float64
+	CaveThreshold    float64
+	CaveSurfaceDepth int // Blocks below the surface over which caves fade in
+
+	// Ore veins replacing stone, see OreVein. Changes only affect chunks
+	// generated afterwards, as for the cave settings.
+	Ores []OreVein
+
+	// Chunks UpdateChunks still has to generate, nearest first. Rebuilt
+	// when the player changes chunk or the render distance changes.
+	genQueue       [][2]int
+	genQueueCenter [2]int
+	genQueueRadius int
+
+	// Cached slice of chunks, rebuilt only when chunks load or unload so
+	// per-frame iteration doesn't allocate
+	chunkList      []*Chunk
+	chunkListDirty bool
+
+	// Saving, see save.go. saveDir is empty until Save or Load is called,
+	// dirty chunks stay loaded until then.
+	saveDir string
+	saved   map[[2]int]bool // Chunks that have a file in saveDir
+
+	// Background generation, see streaming.go
+	workerCount int
+	jobs        chan [2]int
+	results     chan *Chunk
+	pending     map[[2]int]bool
+}
+
+// EditValidator decides whether a block change may happen. Returning false
+// cancels the edit, e.g. for spawn protection. State changes, like toggling
+// a lamp, are checked with old and new the same type.
+type EditValidator func(x, y, z int, old, new BlockType) bool
+
+// BlockChangedFunc observes a block change after it has been applied and
+// the area remeshed
+type BlockChangedFunc func(x, y, z int, old, new BlockType)
+
+// AddBlockChangedListener adds an observer to OnBlockChanged, after any
+// already installed, so several systems can react to the same change
+func (w *World) AddBlockChangedListener(listener BlockChangedFunc) {
+	previous := w.OnBlockChanged
+	if previous == nil {
+		w.OnBlockChanged = listener
+		return
+	}
+	w.OnBlockChanged = func(x, y, z int, old, new BlockType) {
+		previous(x, y, z, old, new)
+		listener(x, y, z, old, new)
+	}
+}
+
+// NewWorld creates a world whose terrain is generated from seed. The same
+// seed always produces the same world.
+func NewWorld(seed int64) *World {
+	w := newWorld(seed)
+	w.generateSpawn()
+	return w
+}
+
+// NewHeadlessWorld creates a world of empty chunks, all air, within radius
+// chunks of the origin. It never touches OpenGL and generates nothing, so
+// it works without a window, e.g. in tests. Build in it with SetBlock.
+func NewHeadlessWorld(radius int) *World {
+	w := newWorld(0)
+	w.headless = true
+	for x := -radius; x <= radius; x++ {
+		for z := -radius; z <= radius; z++ {
+			chunk := &Chunk{X: x, Z: z}
+			chunk.computeLight()
+			w.addChunk(chunk)
+		}
+	}
+	return w
 }
 
-func NewWorld() *World {
-	w := &World{
-		chunks: make(map[[2]int]*Chunk),
-		noise:  opensimplex.NewNormalized(12345),
+// newWorld is NewWorld without the spawn chunks, which need a GL context
+// to mesh
+func newWorld(seed int64) *World {
+	gen := DefaultGenConfig()
+	gen.Seed = seed
+	return &World{
+		chunks:         make(map[[2]int]*Chunk),
+		noise:          opensimplex.NewNormalized(gen.Seed),
+		gen:            gen,
+		renderDistance: DefaultRenderDistance,
+		meshUpload:     UploadOrphan,
+		workerCount:    runtime.NumCPU(),
+		pending:        make(map[[2]int]bool),
+
+		ChunkBudget: DefaultChunkBudget,
+
+		CaveFrequency:    0.04,
+		CaveThreshold:    0.06,
+		CaveSurfaceDepth: 8,
+		Ores:             DefaultOres(),
 	}
+}
 
-	// Generate initial chunks around spawn
+// generateSpawn builds the chunks around spawn synchronously
+func (w *World) generateSpawn() {
 	for x := -2; x <= 2; x++ {
 		for z := -2; z <= 2; z++ {
-			chunk := w.generateChunk(x, z)
-			w.chunks[[2]int{x, z}] = chunk
-			chunk.generateMesh(w)
+			w.addChunk(w.loadOrGenerateChunk(x, z))
 		}
 	}
-
-	return w
 }
 
 func (w *World) generateChunk(chunkX, chunkZ int) *Chunk {
@@ -46,6 +175,10 @@ func (w *World) generateChunk(chunkX, chunkZ int) *Chunk {
 		Z: chunkZ,
 	}
 
+	// Surface height and block biome of each column, for the decoration pass
+	var heights [ChunkSize][ChunkSize]int
+	var biomes [ChunkSize][ChunkSize]Biome
+
 	for x := 0; x < ChunkSize; x++ {
 		for z := 0; z < ChunkSize; z++ {
 
@@ -59,21 +192,13 @@ func (w *World) generateChunk(chunkX, chunkZ int) *Chunk {
 			ruggedness := w.noise.Eval2(worldX*0.004, worldZ*0.004)
 			jitter := w.noise.Eval2(worldX*0.5, worldZ*0.5)
 
-			mountainShape := math.Abs(w.noise.Eval2(worldX*0.015, worldZ*0.015))
+			mountainShape := math.Abs(w.fbm(worldX*0.015, worldZ*0.015,
+				w.gen.MountainOctaves, w.gen.Lacunarity, w.gen.Persistence))
 			mountainShape = math.Pow(mountainShape, 2)
 
-			baseElevation := w.noise.Eval2(worldX*0.005, worldZ*0.005)
-			var amplitude float64
-
-			if ruggedness > 0.6 {
-				factor := math.Min((ruggedness-0.6)/0.4, 1.0)
-				amplitude = 40.0 + (factor * 100.0)
-			} else if ruggedness > 0.2 {
-				factor := math.Min((ruggedness-0.2)/0.4, 1.0)
-				amplitude = 10.0 + (factor * 30.0)
-			} else {
-				amplitude = 2.0 + ((ruggedness + 1.0) * 8.0)
-			}
+			baseElevation := w.fbm(worldX*0.005, worldZ*0.005,
+				w.gen.ElevationOctaves, w.gen.Lacunarity, w.gen.Persistence)
+			amplitude := mountainAmplitude(ruggedness)
 
 			baseLevel := 25.0
 			height := baseLevel +
@@ -81,6 +206,10 @@ func (w *World) generateChunk(chunkX, chunkZ int) *Chunk {
 				(mountainShape * amplitude) +
 				(w.noise.Eval2(worldX*0.1, worldZ*0.1) * 2.0)
 
+			if w.gen.Rivers {
+				height = w.carveRiver(worldX, worldZ, height)
+			}
+
 			if height < 2 {
 				height = 2
 			}
@@ -89,50 +218,95 @@ func (w *World) generateChunk(chunkX, chunkZ int) *Chunk {
 			}
 
 			heightInt := int(height)
+			heights[x][z] = heightInt
+			biome := w.blendedBiome(absX, absZ, ruggedness)
+			biomes[x][z] = biome
+			def := &biomeDefs[biome]
 
 			for y := 0; y < ChunkHeight; y++ {
 				if y == 0 {
-					chunk.Blocks[x][y][z].Type = BlockStone
+					chunk.Set(x, y, z, Block{Type: BlockStone})
 					continue
 				}
 				if y > heightInt {
-					chunk.Blocks[x][y][z].Type = BlockAir
+					// Sea level fill, also floods carved river channels
+					if y <= SeaLevel {
+						chunk.Set(x, y, z, Block{Type: BlockWater})
+					} else {
+						chunk.Set(x, y, z, Block{Type: BlockAir})
+					}
 					continue
 				}
 
 				if y == heightInt {
 					if y > 90+int(jitter*11) {
-						chunk.Blocks[x][y][z].Type = BlockSnow
+						chunk.Set(x, y, z, Block{Type: BlockSnow})
 					} else if y > 72+int(jitter*7) {
-						chunk.Blocks[x][y][z].Type = BlockStone
+						chunk.Set(x, y, z, Block{Type: BlockStone})
 					} else if y <= 33 {
-						chunk.Blocks[x][y][z].Type = BlockSand
+						chunk.Set(x, y, z, Block{Type: BlockSand})
 					} else {
-						chunk.Blocks[x][y][z].Type = BlockGrass
+						chunk.Set(x, y, z, Block{Type: def.top})
 					}
 				} else if y > heightInt-4 {
 					if y > 80 {
-						chunk.Blocks[x][y][z].Type = BlockStone
+						chunk.Set(x, y, z, Block{Type: BlockStone})
 					} else if heightInt <= 33 {
-						chunk.Blocks[x][y][z].Type = BlockSand
+						chunk.Set(x, y, z, Block{Type: BlockSand})
 					} else {
-						chunk.Blocks[x][y][z].Type = BlockDirt
+						chunk.Set(x, y, z, Block{Type: def.filler})
 					}
 				} else {
-					chunk.Blocks[x][y][z].Type = BlockStone
+					chunk.Set(x, y, z, Block{Type: BlockStone})
 				}
 			}
+
+			// Caves go in after the surface blocks so the top layer is known
+			w.carveCaves(chunk, x, z, heightInt)
+			w.placeOres(chunk, x, z, heightInt)
 		}
 	}
+
+	w.decorateChunk(chunk, &heights, &biomes)
+	chunk.computeLight()
 	return chunk
 }
 
+// Seed returns the seed the world's terrain is generated from
+func (w *World) Seed() int64 {
+	return w.gen.Seed
+}
+
+// GetChunks returns all loaded chunks. The slice is shared and reused
+// between calls, so callers must not modify or hold on to it.
 func (w *World) GetChunks() []*Chunk {
-	chunks := make([]*Chunk, 0, len(w.chunks))
-	for _, chunk := range w.chunks {
-		chunks = append(chunks, chunk)
+	if w.chunkListDirty {
+		w.chunkList = w.chunkList[:0]
+		for _, chunk := range w.chunks {
+			w.chunkList = append(w.chunkList, chunk)
+		}
+		w.chunkListDirty = false
 	}
-	return chunks
+	return w.chunkList
+}
+
+// SurfaceHeight returns the y of the topmost non-air block in a column,
+// or ChunkHeight if the column is empty. The column's chunk is generated
+// (and meshed) first if it isn't loaded, so call it from the GL thread.
+func (w *World) SurfaceHeight(x, z int) int {
+	chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
+	if !exists {
+		chunk = w.loadOrGenerateChunk(chunkX, chunkZ)
+		w.addChunk(chunk)
+	}
+
+	for y := ChunkHeight - 1; y >= 0; y-- {
+		if chunk.Get(localX, y, localZ).Type != BlockAir {
+			return y
+		}
+	}
+	return ChunkHeight
 }
 
 func (w *World) GetBlock(x, y, z int) BlockType {
@@ -140,115 +314,349 @@ func (w *World) GetBlock(x, y, z int) BlockType {
 		return BlockAir
 	}
 
-	chunkX := x / ChunkSize
-	chunkZ := z / ChunkSize
-	localX := x % ChunkSize
-	localZ := z % ChunkSize
+	chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
 
-	if localX < 0 {
-		localX += ChunkSize
-		chunkX--
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
+	if !exists {
+		return BlockAir
 	}
-	if localZ < 0 {
-		localZ += ChunkSize
-		chunkZ--
+
+	return chunk.Get(localX, y, localZ).Type
+}
+
+// SetBlock changes a block, remeshes around it and notifies OnBlockChanged.
+// Orientable blocks stand upright, see SetBlockOriented.
+// Returns false if the position isn't loaded or the edit validator rejected
+// the change.
+func (w *World) SetBlock(x, y, z int, blockType BlockType) bool {
+	return w.SetBlockOriented(x, y, z, blockType, AxisY)
+}
+
+// SetBlockOriented is SetBlock for a block turned to run along axis. The
+// axis is ignored for blocks that aren't Orientable.
+func (w *World) SetBlockOriented(x, y, z int, blockType BlockType, axis Axis) bool {
+	if y < 0 || y >= ChunkHeight {
+		return false
 	}
 
-	chunk, exists := w.chunks[[2]int{chunkX, chunkZ}]
+	chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
+
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
 	if !exists {
-		return BlockAir
+		return false
+	}
+
+	old := chunk.Get(localX, y, localZ).Type
+	if w.editValidator != nil && !w.editValidator(x, y, z, old, blockType) {
+		return false
+	}
+
+	block := Block{Type: blockType}
+	if GetBlockDef(blockType).Orientable {
+		block.State = packState(0, axis)
+	}
+
+	// Writing the block that is already there changes nothing
+	if chunk.Get(localX, y, localZ) == block {
+		return true
 	}
 
-	return chunk.Blocks[localX][y][localZ].Type
+	chunk.Set(localX, y, localZ, block)
+	chunk.dirty = true
+	chunk.surfaceKnown = false
+
+	w.remeshAround(chunk, localX, localZ, !looksSameToNeighbors(old, blockType))
+	if w.OnBlockChanged != nil {
+		w.OnBlockChanged(x, y, z, old, blockType)
+	}
+	return true
+}
+
+// looksSameToNeighbors reports whether swapping a for b leaves the faces
+// and ambient occlusion of the blocks around it as they were. Neighbors
+// only tell air, each liquid and solid apart, see buildVertices.
+func looksSameToNeighbors(a, b BlockType) bool {
+	return a == b || (IsSolid(a) && IsSolid(b))
+}
+
+// SetEditValidator installs a hook consulted before every SetBlock and
+// SetBlockState.
+// Pass nil to allow all edits again.
+func (w *World) SetEditValidator(validator EditValidator) {
+	w.editValidator = validator
+}
+
+// IsWater reports whether the block at a world position is water
+func (w *World) IsWater(x, y, z int) bool {
+	return w.GetBlock(x, y, z) == BlockWater
 }
 
-func (w *World) SetBlock(x, y, z int, blockType BlockType) {
+func (w *World) GetBlockState(x, y, z int) uint8 {
 	if y < 0 || y >= ChunkHeight {
-		return
+		return 0
 	}
 
-	chunkX := x / ChunkSize
-	chunkZ := z / ChunkSize
-	localX := x % ChunkSize
-	localZ := z % ChunkSize
+	chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
 
-	if localX < 0 {
-		localX += ChunkSize
-		chunkX--
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
+	if !exists {
+		return 0
 	}
-	if localZ < 0 {
-		localZ += ChunkSize
-		chunkZ--
+
+	return chunk.Get(localX, y, localZ).Variant()
+}
+
+// SetBlockState changes the state of a block in place, keeping its type
+// and axis. Returns false if the position isn't loaded or the edit
+// validator rejected the change.
+func (w *World) SetBlockState(x, y, z int, state uint8) bool {
+	if y < 0 || y >= ChunkHeight {
+		return false
 	}
 
-	chunk, exists := w.chunks[[2]int{chunkX, chunkZ}]
+	chunkX, chunkZ, localX, localZ := worldToChunk(x, z)
+
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
 	if !exists {
-		return
+		return false
+	}
+	block := chunk.Get(localX, y, localZ)
+	if block.Variant() == state {
+		return true
+	}
+	if w.editValidator != nil && !w.editValidator(x, y, z, block.Type, block.Type) {
+		return false
 	}
 
-	chunk.Blocks[localX][y][localZ].Type = blockType
+	block.State = packState(state, block.Axis())
+	chunk.Set(localX, y, localZ, block)
+	chunk.dirty = true
 
-	// Regenerate mesh
-	chunk.generateMesh(w)
+	// Neighbors don't see state, only the light it may give off
+	w.remeshAround(chunk, localX, localZ, false)
+	return true
+}
 
-	// Update neighboring chunks if block is on edge
-	if localX == 0 {
-		if neighbor, ok := w.chunks[[2]int{chunkX - 1, chunkZ}]; ok {
-			neighbor.generateMesh(w)
+// remeshAround relights and rebuilds a chunk after an edit, plus any
+// neighbor whose light changed. With neighborsSeeEdit the chunks sharing
+// the edited edge or corner are rebuilt too, others can't tell.
+func (w *World) remeshAround(chunk *Chunk, localX, localZ int, neighborsSeeEdit bool) {
+	chunkX, chunkZ := chunk.X, chunk.Z
+	chunk.edited = true
+
+	remesh := w.relightAround(chunk)
+	remesh[chunk] = true
+
+	if neighborsSeeEdit {
+		// Which edges the block is on, if any
+		dx, dz := 0, 0
+		if localX == 0 {
+			dx = -1
+		} else if localX == ChunkSize-1 {
+			dx = 1
 		}
-	} else if localX == ChunkSize-1 {
-		if neighbor, ok := w.chunks[[2]int{chunkX + 1, chunkZ}]; ok {
-			neighbor.generateMesh(w)
+		if localZ == 0 {
+			dz = -1
+		} else if localZ == ChunkSize-1 {
+			dz = 1
+		}
+
+		// The chunks across those edges, and the diagonal one whose corner
+		// blocks take ambient occlusion from a corner block
+		for _, offset := range [][2]int{{dx, 0}, {0, dz}, {dx, dz}} {
+			if offset == [2]int{} {
+				continue
+			}
+			if neighbor, ok := w.chunks[chunkKey(chunkX+offset[0], chunkZ+offset[1])]; ok {
+				remesh[neighbor] = true
+			}
 		}
 	}
 
-	if localZ == 0 {
-		if neighbor, ok := w.chunks[[2]int{chunkX, chunkZ - 1}]; ok {
-			neighbor.generateMesh(w)
+	for c := range remesh {
+		c.generateMesh(w)
+	}
+}
+
+// addChunk puts a generated or loaded chunk into the world, lets light flow
+// across its borders and meshes it
+func (w *World) addChunk(chunk *Chunk) {
+	w.chunks[chunkKey(chunk.X, chunk.Z)] = chunk
+	w.chunkListDirty = true
+
+	for neighbor := range w.stitchLight(chunk) {
+		neighbor.generateMesh(w)
+	}
+	chunk.generateMesh(w)
+}
+
+// UseBlock runs the OnUse handler of the block at the given position.
+// Returns false if the block has no handler or declined the use.
+func (w *World) UseBlock(x, y, z int) bool {
+	def := GetBlockDef(w.GetBlock(x, y, z))
+	if def.OnUse == nil {
+		return false
+	}
+	return def.OnUse(w, x, y, z)
+}
+
+// IsChunkReady reports whether the chunk containing world position x, z has
+// been generated and meshed, i.e. it's safe to stand on
+func (w *World) IsChunkReady(x, z float32) bool {
+	chunkX, chunkZ := ChunkOf(x, z)
+
+	chunk, exists := w.chunks[chunkKey(chunkX, chunkZ)]
+	return exists && chunk.meshed
+}
+
+// ChunkStatusGrid fills out with the status of every chunk in the square of
+// the given radius around a chunk, row by row along Z. out is reused when it
+// is large enough so debug views can call this every frame.
+func (w *World) ChunkStatusGrid(centerX, centerZ, radius int, out []ChunkStatus) []ChunkStatus {
+	side := radius*2 + 1
+	if cap(out) < side*side {
+		out = make([]ChunkStatus, side*side)
+	}
+	out = out[:side*side]
+
+	i := 0
+	for z := centerZ - radius; z <= centerZ+radius; z++ {
+		for x := centerX - radius; x <= centerX+radius; x++ {
+			if chunk, ok := w.chunks[chunkKey(x, z)]; ok {
+				out[i] = chunk.Status()
+			} else if w.pending[chunkKey(x, z)] {
+				out[i] = ChunkQueued
+			} else {
+				out[i] = ChunkMissing
+			}
+			i++
 		}
-	} else if localZ == ChunkSize-1 {
-		if neighbor, ok := w.chunks[[2]int{chunkX, chunkZ + 1}]; ok {
-			neighbor.generateMesh(w)
+	}
+	return out
+}
+
+// ChunkSurfaceGrid is ChunkStatusGrid for the most common surface block of
+// each chunk, Air where no chunk is loaded
+func (w *World) ChunkSurfaceGrid(centerX, centerZ, radius int, out []BlockType) []BlockType {
+	side := radius*2 + 1
+	if cap(out) < side*side {
+		out = make([]BlockType, side*side)
+	}
+	out = out[:side*side]
+
+	i := 0
+	for z := centerZ - radius; z <= centerZ+radius; z++ {
+		for x := centerX - radius; x <= centerX+radius; x++ {
+			if chunk, ok := w.chunks[chunkKey(x, z)]; ok {
+				out[i] = chunk.Surface()
+			} else {
+				out[i] = BlockAir
+			}
+			i++
 		}
 	}
+	return out
+}
+
+// SetMeshUploadMode chooses how chunk meshes are re-uploaded after edits
+func (w *World) SetMeshUploadMode(mode MeshUploadMode) {
+	w.meshUpload = mode
+}
+
+func (w *World) RenderDistance() int {
+	return w.renderDistance
+}
+
+// SetRenderDistance clamps to [MinRenderDistance, MaxRenderDistance]. Chunks
+// load or unload to match on the next UpdateChunks.
+func (w *World) SetRenderDistance(distance int) {
+	if distance < MinRenderDistance {
+		distance = MinRenderDistance
+	}
+	if distance > MaxRenderDistance {
+		distance = MaxRenderDistance
+	}
+	w.renderDistance = distance
 }
 
+// UpdateChunks generates up to ChunkBudget missing chunks in render
+// distance, nearest first, and unloads far ones. The rest are queued for
+// later calls.
 func (w *World) UpdateChunks(playerX, playerZ float32) {
 	// Calculate which chunk the player is in
-	playerChunkX := int(math.Floor(float64(playerX))) / ChunkSize
-	playerChunkZ := int(math.Floor(float64(playerZ))) / ChunkSize
-
-	// Generate chunks in render distance
-	for x := playerChunkX - RenderDistance; x <= playerChunkX+RenderDistance; x++ {
-		for z := playerChunkZ - RenderDistance; z <= playerChunkZ+RenderDistance; z++ {
-			chunkKey := [2]int{x, z}
-
-			// If chunk doesn't exist, generate it
-			if _, exists := w.chunks[chunkKey]; !exists {
-				chunk := w.generateChunk(x, z)
-				w.chunks[chunkKey] = chunk
-				chunk.generateMesh(w)
+	playerChunkX, playerChunkZ := ChunkOf(playerX, playerZ)
+
+	center := chunkKey(playerChunkX, playerChunkZ)
+	if len(w.genQueue) == 0 || center != w.genQueueCenter || w.renderDistance != w.genQueueRadius {
+		w.genQueue = w.missingChunks(playerChunkX, playerChunkZ)
+		w.genQueueCenter, w.genQueueRadius = center, w.renderDistance
+	}
+
+	for budget := max(w.ChunkBudget, 1); budget > 0 && len(w.genQueue) > 0; {
+		key := w.genQueue[0]
+		w.genQueue = w.genQueue[1:]
+		if _, exists := w.chunks[key]; exists {
+			continue
+		}
+		w.addChunk(w.loadOrGenerateChunk(key[0], key[1]))
+		budget--
+	}
+
+	w.unloadFarChunks(playerChunkX, playerChunkZ)
+}
+
+// missingChunks lists the chunks in render distance that are neither
+// loaded nor being generated, nearest to the player first
+func (w *World) missingChunks(playerChunkX, playerChunkZ int) [][2]int {
+	var missing [][2]int
+	for x := playerChunkX - w.renderDistance; x <= playerChunkX+w.renderDistance; x++ {
+		for z := playerChunkZ - w.renderDistance; z <= playerChunkZ+w.renderDistance; z++ {
+			key := chunkKey(x, z)
+			if _, exists := w.chunks[key]; !exists && !w.pending[key] {
+				missing = append(missing, key)
 			}
 		}
 	}
 
-	// Unload chunks that are too far away
+	distSq := func(key [2]int) int {
+		dx, dz := key[0]-playerChunkX, key[1]-playerChunkZ
+		return dx*dx + dz*dz
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return distSq(missing[i]) < distSq(missing[j])
+	})
+	return missing
+}
+
+// unloadFarChunks drops chunks well outside the render distance, freeing
+// their GL buffers
+func (w *World) unloadFarChunks(playerChunkX, playerChunkZ int) {
 	toDelete := make([][2]int, 0)
 	for key := range w.chunks {
 		dx := key[0] - playerChunkX
 		dz := key[1] - playerChunkZ
 		distance := math.Sqrt(float64(dx*dx + dz*dz))
 
-		if distance > float64(RenderDistance+2) {
-			if w.chunks[key].Mesh != nil {
-				gl.DeleteVertexArrays(1, &w.chunks[key].Mesh.VAO)
-				gl.DeleteBuffers(1, &w.chunks[key].Mesh.VBO)
+		if distance > float64(w.renderDistance+2) {
+			// Keep edits. If the save fails, or there is nowhere to save
+			// yet, the chunk stays loaded so nothing is lost, and it is
+			// retried on the next update.
+			if w.chunks[key].dirty {
+				if w.saveDir == "" {
+					continue
+				}
+				if err := w.saveChunk(w.chunks[key]); err != nil {
+					w.reportSaveError(err)
+					continue
+				}
 			}
+			w.chunks[key].deleteMeshes()
 			toDelete = append(toDelete, key)
 		}
 	}
 
 	for _, key := range toDelete {
 		delete(w.chunks, key)
+		w.chunkListDirty = true
 	}
 }
@@ -0,0 +1,73 @@
+package world
+
+import "testing"
+
+// newTestWorld is a world holding empty chunks around the origin. Nothing
+// is meshed, so tests run without a GL context.
+func newTestWorld(t *testing.T) *World {
+	t.Helper()
+	w := newWorld(1)
+	for x := -1; x <= 1; x++ {
+		for z := -1; z <= 1; z++ {
+			w.chunks[chunkKey(x, z)] = &Chunk{X: x, Z: z}
+		}
+	}
+	return w
+}
+
+func TestEditValidatorRejects(t *testing.T) {
+	w := newTestWorld(t)
+	chunk := w.chunks[chunkKey(0, 0)]
+	chunk.Set(2, 10, 3, Block{Type: BlockLamp})
+
+	var checked [][2]BlockType
+	w.SetEditValidator(func(x, y, z int, old, new BlockType) bool {
+		checked = append(checked, [2]BlockType{old, new})
+		return false
+	})
+
+	if w.SetBlock(5, 10, 5, BlockStone) {
+		t.Error("SetBlock succeeded past the validator")
+	}
+	if got := w.GetBlock(5, 10, 5); got != BlockAir {
+		t.Errorf("rejected SetBlock left %v, want air", got)
+	}
+
+	if w.UseBlock(2, 10, 3) {
+		t.Error("toggling the lamp succeeded past the validator")
+	}
+	if w.SetBlockState(2, 10, 3, 1) {
+		t.Error("SetBlockState succeeded past the validator")
+	}
+	if got := w.GetBlockState(2, 10, 3); got != 0 {
+		t.Errorf("rejected state change left state %d, want 0", got)
+	}
+	if chunk.dirty {
+		t.Error("rejected edits marked the chunk dirty")
+	}
+
+	want := [][2]BlockType{{BlockAir, BlockStone}, {BlockLamp, BlockLamp}, {BlockLamp, BlockLamp}}
+	if len(checked) != len(want) {
+		t.Fatalf("validator saw %v, want %v", checked, want)
+	}
+	for i := range want {
+		if checked[i] != want[i] {
+			t.Errorf("validator call %d saw %v, want %v", i, checked[i], want[i])
+		}
+	}
+}
+
+func TestEditedChunkStatusIsDirty(t *testing.T) {
+	w := NewHeadlessWorld(1)
+	if got := w.chunks[chunkKey(0, 0)].Status(); got != ChunkEmpty {
+		t.Fatalf("fresh empty chunk has status %v, want ChunkEmpty", got)
+	}
+	w.SetBlock(3, 20, 3, BlockStone)
+	grid := w.ChunkStatusGrid(0, 0, 1, nil)
+	if got := grid[4]; got != ChunkDirty {
+		t.Errorf("edited chunk has status %v, want ChunkDirty", got)
+	}
+	if got := grid[0]; got != ChunkEmpty {
+		t.Errorf("untouched neighbor has status %v, want ChunkEmpty", got)
+	}
+}